@@ -29,6 +29,6 @@ func main() {
 	}
 
 	if err := cmd.Run(opts); err != nil {
-		os.Exit(1)
+		os.Exit(cmd.ExitCode(err))
 	}
 }
@@ -0,0 +1,97 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package api defines the parts of the DNSimple API used by the commands, as
+// narrow interfaces that tests can replace one service at a time.
+package api
+
+import (
+	"context"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+)
+
+type Accounts interface {
+	ListAccounts(ctx context.Context, options *dnsimple.ListOptions) (*dnsimple.AccountsResponse, error)
+}
+
+type Identity interface {
+	Whoami(ctx context.Context) (*dnsimple.WhoamiResponse, error)
+}
+
+type Domains interface {
+	ListDomains(ctx context.Context, accountID string, options *dnsimple.DomainListOptions) (*dnsimple.DomainsResponse, error)
+	CreateDomain(ctx context.Context, accountID string, domainAttributes dnsimple.Domain) (*dnsimple.DomainResponse, error)
+	GetDomain(ctx context.Context, accountID string, domainIdentifier string) (*dnsimple.DomainResponse, error)
+	DeleteDomain(ctx context.Context, accountID string, domainIdentifier string) (*dnsimple.DomainResponse, error)
+}
+
+type Collaborators interface {
+	ListCollaborators(ctx context.Context, accountID, domainIdentifier string, options *dnsimple.ListOptions) (*dnsimple.CollaboratorsResponse, error)
+	AddCollaborator(ctx context.Context, accountID string, domainIdentifier string, attributes dnsimple.CollaboratorAttributes) (*dnsimple.CollaboratorResponse, error)
+	RemoveCollaborator(ctx context.Context, accountID string, domainIdentifier string, collaboratorID int64) (*dnsimple.CollaboratorResponse, error)
+}
+
+type DelegationSignerRecords interface {
+	ListDelegationSignerRecords(ctx context.Context, accountID string, domainIdentifier string, options *dnsimple.ListOptions) (*dnsimple.DelegationSignerRecordsResponse, error)
+	CreateDelegationSignerRecord(ctx context.Context, accountID string, domainIdentifier string, dsRecordAttributes dnsimple.DelegationSignerRecord) (*dnsimple.DelegationSignerRecordResponse, error)
+	GetDelegationSignerRecord(ctx context.Context, accountID string, domainIdentifier string, dsRecordID int64) (*dnsimple.DelegationSignerRecordResponse, error)
+	DeleteDelegationSignerRecord(ctx context.Context, accountID string, domainIdentifier string, dsRecordID int64) (*dnsimple.DelegationSignerRecordResponse, error)
+}
+
+type DNSSEC interface {
+	EnableDnssec(ctx context.Context, accountID string, domainIdentifier string) (*dnsimple.DnssecResponse, error)
+	DisableDnssec(ctx context.Context, accountID string, domainIdentifier string) (*dnsimple.DnssecResponse, error)
+	GetDnssec(ctx context.Context, accountID string, domainIdentifier string) (*dnsimple.DnssecResponse, error)
+}
+
+type Zones interface {
+	ListZones(ctx context.Context, accountID string, options *dnsimple.ZoneListOptions) (*dnsimple.ZonesResponse, error)
+	ListRecords(ctx context.Context, accountID string, zoneName string, options *dnsimple.ZoneRecordListOptions) (*dnsimple.ZoneRecordsResponse, error)
+	CreateRecord(ctx context.Context, accountID string, zoneName string, recordAttributes dnsimple.ZoneRecordAttributes) (*dnsimple.ZoneRecordResponse, error)
+	UpdateRecord(ctx context.Context, accountID string, zoneName string, recordID int64, recordAttributes dnsimple.ZoneRecordAttributes) (*dnsimple.ZoneRecordResponse, error)
+	DeleteRecord(ctx context.Context, accountID string, zoneName string, recordID int64) (*dnsimple.ZoneRecordResponse, error)
+}
+
+type Templates interface {
+	ApplyTemplate(ctx context.Context, accountID string, templateIdentifier string, domainIdentifier string) (*dnsimple.TemplateResponse, error)
+}
+
+// Client groups the services used by the commands and the terminal UI.
+type Client struct {
+	Accounts                Accounts
+	Identity                Identity
+	Domains                 Domains
+	Collaborators           Collaborators
+	DelegationSignerRecords DelegationSignerRecords
+	DNSSEC                  DNSSEC
+	Templates               Templates
+	Zones                   Zones
+}
+
+// New returns a Client backed by the services of c.
+func New(c *dnsimple.Client) *Client {
+	return &Client{
+		Accounts:                c.Accounts,
+		Identity:                c.Identity,
+		Domains:                 c.Domains,
+		Collaborators:           c.Domains,
+		DelegationSignerRecords: c.Domains,
+		DNSSEC:                  c.Domains,
+		Templates:               c.Templates,
+		Zones:                   c.Zones,
+	}
+}
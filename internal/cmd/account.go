@@ -0,0 +1,155 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/api"
+	"github.com/edsonmichaque/dnsimple-cli/internal/config"
+	"github.com/spf13/viper"
+)
+
+// lookupAccounts returns the accounts the token gives access to. For account
+// tokens, the account the token belongs to is returned as well.
+func lookupAccounts(ctx context.Context, client *api.Client) ([]dnsimple.Account, *dnsimple.Account, error) {
+	whoami, err := client.Identity.Whoami(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if account := whoami.Data.Account; account != nil {
+		return []dnsimple.Account{*account}, account, nil
+	}
+
+	resp, err := client.Accounts.ListAccounts(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resp.Data, nil, nil
+}
+
+// resolveAccountIDs turns the account emails of a comma separated list into
+// the IDs of the accounts. Other values are kept as is.
+func resolveAccountIDs(ctx context.Context, client *api.Client, value string) (string, error) {
+	if !strings.Contains(value, "@") {
+		return value, nil
+	}
+
+	accounts, _, err := lookupAccounts(ctx, client)
+	if err != nil {
+		return "", err
+	}
+
+	values := splitAccounts(value)
+
+	for i, v := range values {
+		if !strings.Contains(v, "@") {
+			continue
+		}
+
+		found := false
+
+		for _, account := range accounts {
+			if strings.EqualFold(account.Email, v) {
+				values[i] = strconv.FormatInt(account.ID, 10)
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return "", fmt.Errorf("no account with email %s", v)
+		}
+	}
+
+	return strings.Join(values, ","), nil
+}
+
+// accountResolver returns a config.AccountResolver replacing the account
+// emails of a configuration with the IDs of the accounts, so that commands
+// only see IDs. Only commands loading a configuration to call the API look
+// the accounts up.
+func accountResolver(ctx context.Context, opts *Options) func(cfg *config.Config) (string, error) {
+	return func(cfg *config.Config) (string, error) {
+		return resolveAccountIDs(ctx, opts.createClient(cfg.BaseURL, cfg.AccessToken), cfg.Account)
+	}
+}
+
+// isMultiAccount reports whether the command is asked to run against more
+// than one account.
+func isMultiAccount() bool {
+	return viper.GetBool(flagAllAccounts) || strings.Contains(viper.GetString(configAccount), ",")
+}
+
+// targetAccounts returns the accounts a read command runs against: every
+// account of the token with --all-accounts, or those listed in --account.
+func targetAccounts(ctx context.Context, client *api.Client, cfg *config.Config) ([]string, error) {
+	if !cfg.AllAccounts {
+		return splitAccounts(cfg.Account), nil
+	}
+
+	accounts, _, err := lookupAccounts(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		ids = append(ids, strconv.FormatInt(account.ID, 10))
+	}
+
+	return ids, nil
+}
+
+// forEachAccount calls fn for every account concurrently and returns the
+// first error.
+func forEachAccount(accounts []string, fn func(account string) error) error {
+	return runConcurrentlyE(len(accounts), defaultConcurrency, func(i int) error {
+		err := fn(accounts[i])
+		if err != nil && len(accounts) > 1 {
+			return fmt.Errorf("account %s: %w", accounts[i], err)
+		}
+
+		return err
+	})
+}
+
+func splitAccounts(value string) []string {
+	var (
+		accounts []string
+		seen     = make(map[string]struct{})
+	)
+
+	for _, account := range strings.Split(value, ",") {
+		account = strings.TrimSpace(account)
+		if _, ok := seen[account]; ok || account == "" {
+			continue
+		}
+
+		seen[account] = struct{}{}
+		accounts = append(accounts, account)
+	}
+
+	return accounts
+}
@@ -0,0 +1,120 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/cmd"
+	"github.com/edsonmichaque/dnsimple-cli/internal/cmdtest"
+	"github.com/edsonmichaque/dnsimple-cli/internal/fakeapi"
+)
+
+// newAccountsServer serves a user token with access to two accounts, each
+// with a domain.
+func newAccountsServer(t *testing.T) *cmdtest.Server {
+	t.Helper()
+
+	api := fakeapi.New(
+		dnsimple.Account{ID: 1010, Email: "ops@example.com"},
+		dnsimple.Account{ID: 2020, Email: "dev@example.com"},
+	)
+
+	for id, name := range map[int64]string{1010: "example.com", 2020: "example.org"} {
+		if _, err := api.AddDomain(id, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server := cmdtest.NewServer(t)
+	server.Fallback = api
+
+	return server
+}
+
+func TestAccountEmail(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		exitCode int
+	}{
+		{
+			name: "account_email",
+			args: []string{"domain", "list", "--account", "DEV@example.com", "-o", "json", "--query", "[].[account_id, name]"},
+		},
+		{
+			name: "account_emails",
+			args: []string{"domain", "list", "--account", "ops@example.com,dev@example.com", "-o", "json", "--query", "[].[account_id, name]"},
+		},
+		{
+			name:     "account_email_unknown",
+			args:     []string{"domain", "list", "--account", "nobody@example.com"},
+			exitCode: cmd.ExitError,
+		},
+		{
+			name:     "account_required",
+			args:     []string{"config", "init", "--account", ""},
+			exitCode: cmd.ExitError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := cmdtest.Run(t, newAccountsServer(t), tt.args...)
+			if res.ExitCode != tt.exitCode {
+				t.Fatalf("exit code %d, want %d (%v)\n%s", res.ExitCode, tt.exitCode, res.Err, res.Stderr)
+			}
+
+			cmdtest.AssertGolden(t, tt.name, res.Stdout+res.Stderr)
+		})
+	}
+}
+
+func TestAccountDetected(t *testing.T) {
+	server := cmdtest.NewServer(t)
+	server.Fallback = fakeapi.New(dnsimple.Account{ID: 1010, Email: "ops@example.com"})
+
+	res := cmdtest.Run(t, server, "config", "init", "--account", "")
+	if res.Err != nil {
+		t.Fatalf("%v\n%s", res.Err, res.Stderr)
+	}
+
+	if got := readProfile(t, "default.yaml").GetString("account"); got != "1010" {
+		t.Errorf("account = %q, want the account of the token", got)
+	}
+}
+
+func TestAccountEmailOnlyResolvedForAPICommands(t *testing.T) {
+	server := cmdtest.NewServer(t)
+
+	for _, args := range [][]string{
+		{"version"},
+		{"config", "view"},
+		{"cache", "clear"},
+	} {
+		res := cmdtest.Run(t, server, append(args, "--account", "dev@example.com")...)
+		if res.Err != nil {
+			t.Errorf("%s: %v\n%s", strings.Join(args, " "), res.Err, res.Stderr)
+		}
+	}
+
+	if requests := server.Requests(); len(requests) != 0 {
+		t.Errorf("sent %d request(s), want none: %v", len(requests), requests)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/edsonmichaque/dnsimple-cli/internal/cmdtest"
+)
+
+func TestCache(t *testing.T) {
+	server := newServer(t)
+	server.Handle(http.MethodGet, "/v2/1010/domains/example.net", http.StatusOK, `{"data":{"id":181985,"name":"example.net","state":"hosted"}}`)
+	server.Handle(http.MethodDelete, "/v2/1010/domains/example.net", http.StatusNoContent, "")
+
+	list := func() {
+		t.Helper()
+
+		if res := cmdtest.Run(t, server, "domain", "list", "--cache-ttl", "10m"); res.Err != nil {
+			t.Fatalf("%v\n%s", res.Err, res.Stderr)
+		}
+	}
+
+	list()
+	fetched := len(server.Requests())
+
+	list()
+	if got := len(server.Requests()); got != fetched {
+		t.Errorf("listing again made %d request(s), want none", got-fetched)
+	}
+
+	if res := cmdtest.Run(t, server, "domain", "list", "--cache-ttl", "10m", "--no-cache"); res.Err != nil {
+		t.Fatalf("%v\n%s", res.Err, res.Stderr)
+	}
+
+	if got := len(server.Requests()); got == fetched {
+		t.Error("--no-cache answered from the cache")
+	}
+
+	if res := cmdtest.Run(t, server, "domain", "delete", "--domain", "example.net", "--confirm", "--dir", t.TempDir()); res.Err != nil {
+		t.Fatalf("%v\n%s", res.Err, res.Stderr)
+	}
+
+	deleted := len(server.Requests())
+
+	list()
+	if got := len(server.Requests()); got == deleted {
+		t.Error("listing after a delete answered from the cache")
+	}
+}
+
+func TestCacheKeyedByToken(t *testing.T) {
+	server := newServer(t)
+
+	for _, token := range []string{cmdtest.Token, "other-token", cmdtest.Token} {
+		if res := cmdtest.Run(t, server, "whoami", "--cache-ttl", "10m", "--access-token", token); res.Err != nil {
+			t.Fatalf("%v\n%s", res.Err, res.Stderr)
+		}
+	}
+
+	// The response fetched with the first token is not served to the second
+	// one, but is to the first one again.
+	if got := len(server.Requests()); got != 2 {
+		t.Errorf("made %d request(s), want one for each token", got)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/edsonmichaque/dnsimple-cli/internal/cmdtest"
+)
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search.yaml")
+	args := []string{"zone", "record", "search", "--content", "203.0.113.10"}
+
+	recorded := cmdtest.Run(t, newServer(t), append(args, "--record-cassette", path)...)
+	if recorded.Err != nil {
+		t.Fatalf("recording: %v\n%s", recorded.Err, recorded.Stderr)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(data), cmdtest.Token) {
+		t.Error("the cassette contains the access token")
+	}
+
+	// The server of the replay has no fixtures, so every answer comes from
+	// the cassette.
+	replayed := cmdtest.Run(t, cmdtest.NewServer(t), append(args, "--replay-cassette", path)...)
+	if replayed.Err != nil {
+		t.Fatalf("replaying: %v\n%s", replayed.Err, replayed.Stderr)
+	}
+
+	if replayed.Stdout != recorded.Stdout {
+		t.Errorf("replayed output\n%s\ndiffers from recorded output\n%s", replayed.Stdout, recorded.Stdout)
+	}
+
+	missing := cmdtest.Run(t, cmdtest.NewServer(t), "domain", "list", "--replay-cassette", path)
+	if missing.Err == nil || !strings.Contains(missing.Err.Error(), "has no interaction for GET /v2/1010/domains") {
+		t.Errorf("replaying a request missing from the cassette: %v", missing.Err)
+	}
+}
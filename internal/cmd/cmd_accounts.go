@@ -17,7 +17,6 @@
 package cmd
 
 import (
-	"context"
 	"errors"
 	"io"
 
@@ -38,6 +37,7 @@ func CmdAccounts(opts *Options) *cobra.Command {
 			dnsimple accounts --output=yaml
 			dnsimple accounts --output=json --query="[].id"
 		`),
+		Annotations: map[string]string{annotationCacheable: "true"},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			if err := viper.BindPFlags(cmd.Flags()); err != nil {
 				panic(err)
@@ -50,7 +50,7 @@ func CmdAccounts(opts *Options) *cobra.Command {
 				return err
 			}
 
-			resp, err := opts.createClient(cfg.BaseURL, cfg.AccessToken).Accounts.ListAccounts(context.Background(), nil)
+			resp, err := opts.createClient(cfg.BaseURL, cfg.AccessToken).Accounts.ListAccounts(cmd.Context(), nil)
 			if err != nil {
 				// TODO: pretty print error returned from the API client
 				return err
@@ -78,14 +78,20 @@ func CmdAccounts(opts *Options) *cobra.Command {
 		},
 	}, opts)
 
-	addOutputFlag(cmd, formatTable)
+	addOutputFlag(cmd, formatTable, formatJSON, formatYAML)
 	addQueryFlag(cmd)
 
 	return cmd
 }
 
-func addOutputFlag(cmd *cobra.Command, f string) {
-	cmd.Flags().StringP(flagOutput, "o", f, "Output format")
+// addOutputFlag adds --output, defaulting to the first of the formats the
+// command supports. The formats are kept for completion.
+func addOutputFlag(cmd *cobra.Command, formats ...string) {
+	cmd.Flags().StringP(flagOutput, "o", formats[0], "Output format")
+
+	if err := cmd.Flags().SetAnnotation(flagOutput, annotationOutputFormats, formats); err != nil {
+		panic(err)
+	}
 }
 
 func addQueryFlag(cmd *cobra.Command) {
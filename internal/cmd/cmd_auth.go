@@ -0,0 +1,367 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/config"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultLoginWait  = 5 * time.Minute
+	oauthCallbackPath = "/callback"
+	oauthStateSize    = 16
+)
+
+func CmdAuth(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Authenticate with DNSimple",
+	}
+
+	cmd.AddCommand(CmdAuthLogin(opts))
+	cmd.AddCommand(CmdAuthLogout(opts))
+	cmd.AddCommand(CmdAuthStatus(opts))
+
+	return cmd
+}
+
+func CmdAuthLogin(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:   "login",
+		Short: "Log in with an OAuth application",
+		Long: heredoc.Doc(`
+			Log in through the DNSimple OAuth authorization code flow.
+
+			The authorization page is opened in a browser and DNSimple redirects back
+			to a server listening on the loopback interface. The access token obtained
+			for the authorization code is stored in the selected profile, encrypted
+			when the profile uses an encrypted secret file.
+
+			The redirect URI of the OAuth application must be http://127.0.0.1:PORT/callback,
+			so a fixed --port is usually required.
+		`),
+		Args: cobra.NoArgs,
+		Example: heredoc.Doc(`
+			dnsimple auth login --client-id abc --client-secret xyz --port 8085
+			dnsimple auth login --profile sandbox --sandbox --no-browser
+		`),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if err := viper.BindPFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.NewWithValidation(false)
+			if err != nil {
+				return err
+			}
+
+			var (
+				clientID     = viper.GetString(configClientID)
+				clientSecret = viper.GetString(configClientSecret)
+			)
+
+			if clientID == "" || clientSecret == "" {
+				return errors.New("--client-id and --client-secret are required")
+			}
+
+			listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(viper.GetInt(flagPort))))
+			if err != nil {
+				return err
+			}
+
+			redirectURI := fmt.Sprintf("http://%s%s", listener.Addr().String(), oauthCallbackPath)
+
+			state, err := randomState()
+			if err != nil {
+				return err
+			}
+
+			apiClient := dnsimple.NewClient(&http.Client{
+				Transport: httpTransport(opts.Stderr),
+				Timeout:   time.Minute,
+			})
+			if cfg.BaseURL != "" {
+				apiClient.BaseURL = cfg.BaseURL
+			}
+
+			authorizeURL := apiClient.Oauth.AuthorizeURL(clientID, &dnsimple.AuthorizationOptions{
+				RedirectURI: redirectURI,
+				State:       state,
+			})
+
+			cmd.PrintErrf("Open the following URL to authorize the CLI:\n\n  %s\n\n", authorizeURL)
+
+			if !viper.GetBool(flagNoBrowser) {
+				if err := openBrowser(authorizeURL); err != nil {
+					cmd.PrintErrf("Could not open a browser: %v\n", err)
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), viper.GetDuration(flagWait))
+			defer cancel()
+
+			code, err := waitForAuthorizationCode(ctx, listener, state)
+			if err != nil {
+				return err
+			}
+
+			token, err := apiClient.Oauth.ExchangeAuthorizationForToken(&dnsimple.ExchangeAuthorizationRequest{
+				Code:         code,
+				ClientID:     clientID,
+				ClientSecret: clientSecret,
+				RedirectURI:  redirectURI,
+				State:        state,
+				GrantType:    dnsimple.AuthorizationCodeGrant,
+			})
+			if err != nil {
+				return err
+			}
+
+			path, err := profileFile()
+			if err != nil {
+				return err
+			}
+
+			if err := storeAccessToken(path, cfg, token.Token, strconv.FormatInt(token.AccountID, 10)); err != nil {
+				return err
+			}
+
+			cmd.Printf("%s Logged in to account %d, profile saved to %s\n", color.GreenString("✓"), token.AccountID, path)
+
+			return nil
+		},
+	}, opts)
+
+	cmd.Flags().String(configClientID, "", "OAuth application client ID")
+	cmd.Flags().String(configClientSecret, "", "OAuth application client secret")
+	cmd.Flags().Int(flagPort, 0, "Port of the loopback redirect server, random when 0")
+	cmd.Flags().Bool(flagNoBrowser, false, "Print the authorization URL without opening a browser")
+	cmd.Flags().Duration(flagWait, defaultLoginWait, "How long to wait for the authorization")
+
+	return cmd
+}
+
+func CmdAuthLogout(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:   "logout",
+		Short: "Remove the access token from the profile",
+		Args:  cobra.NoArgs,
+		Example: heredoc.Doc(`
+			dnsimple auth logout
+			dnsimple auth logout --profile sandbox
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.NewWithValidation(false)
+			if err != nil {
+				return err
+			}
+
+			path, err := profileFile()
+			if err != nil {
+				return err
+			}
+
+			// Profiles copied before copies got a secret file of their own
+			// may still use it.
+			if cfg.SecretFile != "" {
+				if err := removeSecretFile(cfg.SecretFile, viper.GetString(flagProfile)); err != nil {
+					return err
+				}
+			}
+
+			if err := updateProfile(path, nil, configAccessToken, configSecretFile, configKeyFile); err != nil {
+				return err
+			}
+
+			if cfg.TokenCommand != "" {
+				cmd.PrintErrf("The profile still obtains its token from %q\n", cfg.TokenCommand)
+			}
+
+			cmd.Printf("%s Logged out of profile %s\n", color.GreenString("✓"), viper.GetString(flagProfile))
+
+			return nil
+		},
+	}, opts)
+
+	return cmd
+}
+
+func CmdAuthStatus(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:   "status",
+		Short: "Show the authentication status of the profile",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.New()
+			if err != nil {
+				return err
+			}
+
+			baseURL := cfg.BaseURL
+			if baseURL == "" {
+				baseURL = prodBaseURL
+			}
+
+			resp, err := opts.createClient(cfg.BaseURL, cfg.AccessToken).Identity.Whoami(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			identity := "unknown identity"
+			if resp.Data.User != nil {
+				identity = fmt.Sprintf("user %s", resp.Data.User.Email)
+			}
+
+			if resp.Data.Account != nil {
+				identity = fmt.Sprintf("account %s", resp.Data.Account.Email)
+			}
+
+			cmd.Printf("%s Logged in to %s as %s\n", color.GreenString("✓"), baseURL, identity)
+			cmd.Printf("  Profile: %s\n", viper.GetString(flagProfile))
+			cmd.Printf("  Account: %s\n", cfg.Account)
+
+			return nil
+		},
+	}, opts)
+
+	return cmd
+}
+
+// storeAccessToken saves the token in the profile at path, keeping it
+// encrypted when the profile uses an encrypted secret file.
+func storeAccessToken(path string, cfg *config.Config, token, account string) error {
+	settings := map[string]interface{}{
+		configAccount: account,
+	}
+
+	// Keep the profile pointing at the environment the token was issued by.
+	if cfg.Sandbox {
+		settings[flagSandbox] = true
+	} else if cfg.BaseURL != "" {
+		settings[configBaseURL] = cfg.BaseURL
+	}
+
+	if cfg.SecretFile != "" {
+		backend := config.EncryptedFileBackend{Path: cfg.SecretFile, KeyFile: cfg.KeyFile}
+		if err := backend.Store(token); err != nil {
+			return err
+		}
+
+		return updateProfile(path, settings, configAccessToken)
+	}
+
+	settings[configAccessToken] = token
+
+	return updateProfile(path, settings)
+}
+
+// waitForAuthorizationCode serves the OAuth redirect on listener until it
+// receives the authorization code for state or ctx is done.
+func waitForAuthorizationCode(ctx context.Context, listener net.Listener, state string) (string, error) {
+	type result struct {
+		code string
+		err  error
+	}
+
+	results := make(chan result, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(oauthCallbackPath, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		var res result
+
+		switch {
+		case query.Get("error") != "":
+			res.err = fmt.Errorf("authorization failed: %s %s", query.Get("error"), query.Get("error_description"))
+		case query.Get("state") != state:
+			res.err = errors.New("authorization failed: state mismatch")
+		case query.Get("code") == "":
+			res.err = errors.New("authorization failed: no code received")
+		default:
+			res.code = query.Get("code")
+		}
+
+		if res.err != nil {
+			http.Error(w, res.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Authorization complete, you can close this window.")
+		}
+
+		select {
+		case results <- res:
+		default:
+		}
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	defer server.Close()
+
+	select {
+	case res := <-results:
+		return res.code, res.err
+	case <-ctx.Done():
+		return "", fmt.Errorf("waiting for authorization: %w", ctx.Err())
+	}
+}
+
+func randomState() (string, error) {
+	buf := make([]byte, oauthStateSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+
+	return cmd.Start()
+}
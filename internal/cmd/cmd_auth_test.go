@@ -0,0 +1,220 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/cmd"
+	"github.com/edsonmichaque/dnsimple-cli/internal/cmdtest"
+	"github.com/edsonmichaque/dnsimple-cli/internal/fakeapi"
+)
+
+func TestAuthLogin(t *testing.T) {
+	server := cmdtest.NewServer(t)
+	server.Fallback = fakeapi.New(dnsimple.Account{ID: 1010, Email: "ops@example.com"})
+
+	b := &browser{}
+
+	res := cmdtest.RunTee(t, server, b,
+		"auth", "login",
+		"--client-id", "client",
+		"--client-secret", "secret",
+		"--no-browser",
+		"--wait", "10s",
+	)
+	if res.Err != nil {
+		t.Fatalf("%v\n%s", res.Err, res.Stderr)
+	}
+
+	if err := b.wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	profile, err := os.ReadFile(filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "dnsimple", "default.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"access-token: " + fakeapi.Token, "account: \"1010\""} {
+		if !strings.Contains(string(profile), want) {
+			t.Errorf("profile does not contain %q:\n%s", want, profile)
+		}
+	}
+
+	var exchanged bool
+
+	for _, req := range server.Requests() {
+		if req.Method == http.MethodPost && req.Path == "/v2/oauth/access_token" {
+			exchanged = strings.Contains(req.Body, `"client_secret":"secret"`)
+		}
+	}
+
+	if !exchanged {
+		t.Error("the authorization code was not exchanged with the client secret")
+	}
+}
+
+func TestAuthLoginStateMismatch(t *testing.T) {
+	server := cmdtest.NewServer(t)
+	server.Fallback = fakeapi.New(dnsimple.Account{ID: 1010, Email: "ops@example.com"})
+
+	b := &browser{
+		rewrite: func(query url.Values) {
+			query.Set("state", "forged")
+		},
+	}
+
+	res := cmdtest.RunTee(t, server, b,
+		"auth", "login",
+		"--client-id", "client",
+		"--client-secret", "secret",
+		"--no-browser",
+		"--wait", "10s",
+	)
+	if res.Err == nil || !strings.Contains(res.Err.Error(), "state mismatch") {
+		t.Fatalf("got error %v, want a state mismatch", res.Err)
+	}
+
+	if err := b.wait(); err == nil {
+		t.Error("the callback accepted a forged state")
+	}
+}
+
+func TestAuthLoginTimeout(t *testing.T) {
+	server := cmdtest.NewServer(t)
+	server.Fallback = fakeapi.New(dnsimple.Account{ID: 1010, Email: "ops@example.com"})
+
+	res := cmdtest.Run(t, server,
+		"auth", "login",
+		"--client-id", "client",
+		"--client-secret", "secret",
+		"--no-browser",
+		"--wait", "50ms",
+	)
+	if res.ExitCode != cmd.ExitTimeout {
+		t.Errorf("exit code %d, want %d (%v)", res.ExitCode, cmd.ExitTimeout, res.Err)
+	}
+}
+
+func TestAuthLogout(t *testing.T) {
+	tests := []struct {
+		name       string
+		shared     bool
+		wantSecret bool
+	}{
+		{name: "own secret file", wantSecret: false},
+		{name: "shared secret file", shared: true, wantSecret: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := cmdtest.NewServer(t)
+
+			cmdtest.Run(t, server, "config", "profile", "list")
+			dir := writeEncryptedProfile(t, "default")
+
+			if tt.shared {
+				// A copy made by an older version shares the secret file.
+				data, err := os.ReadFile(filepath.Join(dir, "default.yaml"))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if err := os.WriteFile(filepath.Join(dir, "legacy.yaml"), data, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if res := cmdtest.Run(t, server, "auth", "logout"); res.Err != nil {
+				t.Fatalf("%v\n%s", res.Err, res.Stderr)
+			}
+
+			_, err := os.Stat(filepath.Join(dir, "default.secret"))
+			if exists := err == nil; exists != tt.wantSecret {
+				t.Errorf("secret file exists = %t, want %t (%v)", exists, tt.wantSecret, err)
+			}
+		})
+	}
+}
+
+var authorizeURL = regexp.MustCompile(`https?://\S+/oauth/authorize\S*`)
+
+// browser follows the authorization URL printed by auth login, landing on
+// the loopback redirect server of the command like a user's browser would.
+type browser struct {
+	rewrite func(url.Values)
+
+	once sync.Once
+	done chan error
+}
+
+func (b *browser) Write(p []byte) (int, error) {
+	if match := authorizeURL.Find(p); match != nil {
+		b.once.Do(func() {
+			b.done = make(chan error, 1)
+
+			go func() {
+				b.done <- b.open(string(match))
+			}()
+		})
+	}
+
+	return len(p), nil
+}
+
+func (b *browser) open(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	if b.rewrite != nil {
+		query := u.Query()
+		b.rewrite(query)
+		u.RawQuery = query.Encode()
+	}
+
+	resp, err := http.Get(u.String())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("authorization ended with %s", resp.Status)
+	}
+
+	return nil
+}
+
+func (b *browser) wait() error {
+	if b.done == nil {
+		return fmt.Errorf("no authorization URL printed")
+	}
+
+	return <-b.done
+}
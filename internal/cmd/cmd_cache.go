@@ -0,0 +1,68 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+func CmdCache(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the response cache",
+		Long: heredoc.Doc(`
+			Manage the cache of API responses of read commands.
+
+			The cache is disabled unless a time to live is set, with --cache-ttl,
+			DNSIMPLE_CACHE_TTL or "config set cache-ttl 10m". Cached responses
+			are dropped when a command changes the domain or zone they belong to,
+			and --no-cache ignores them.
+		`),
+	}
+
+	cmd.AddCommand(CmdCacheClear(opts))
+
+	return cmd
+}
+
+func CmdCacheClear(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:   "clear",
+		Short: "Remove every cached response",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := cacheDir()
+			if err != nil {
+				return err
+			}
+
+			if err := os.RemoveAll(dir); err != nil {
+				return err
+			}
+
+			cmd.Printf("%s Cleared cache at %s\n", color.GreenString("✓"), dir)
+
+			return nil
+		},
+	}, opts)
+
+	return cmd
+}
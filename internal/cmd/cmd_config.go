@@ -19,12 +19,18 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/dnsimple/dnsimple-go/dnsimple"
 	"github.com/edsonmichaque/dnsimple-cli/internal/config"
+	"github.com/edsonmichaque/dnsimple-cli/internal/format"
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -34,17 +40,38 @@ const (
 	configFormatYAML = "yaml"
 	configFormatTOML = "toml"
 	configFormatYML  = "yml"
+	redacted         = "********"
 )
 
 var (
 	prodBaseURL    = "https://api.dnsimple.com"
 	sandboxBaseURL = "https://api.sandbox.dnsimple.com"
 
-	configProps = map[string]struct{}{
-		configAccount:     {},
-		configBaseURL:     {},
+	// configViewKeys lists the settings shown by "config view", in order.
+	configViewKeys = []string{
+		configAccount,
+		configAccessToken,
+		configBaseURL,
+		flagSandbox,
+		configTokenCommand,
+		configSecretFile,
+		configKeyFile,
+		configCacheTTL,
+	}
+
+	configSecrets = map[string]struct{}{
 		configAccessToken: {},
-		flagSandbox:       {},
+	}
+
+	configProps = map[string]struct{}{
+		configAccount:      {},
+		configBaseURL:      {},
+		configAccessToken:  {},
+		configCacheTTL:     {},
+		configKeyFile:      {},
+		configSecretFile:   {},
+		configTokenCommand: {},
+		flagSandbox:        {},
 	}
 
 	validateConfig = map[string]func(string) (interface{}, error){
@@ -60,6 +87,22 @@ var (
 		configAccessToken: func(value string) (interface{}, error) {
 			return value, nil
 		},
+		configKeyFile: func(value string) (interface{}, error) {
+			return value, nil
+		},
+		configSecretFile: func(value string) (interface{}, error) {
+			return value, nil
+		},
+		configTokenCommand: func(value string) (interface{}, error) {
+			return value, nil
+		},
+		configCacheTTL: func(value string) (interface{}, error) {
+			if _, err := time.ParseDuration(value); err != nil {
+				return nil, err
+			}
+
+			return value, nil
+		},
 	}
 )
 
@@ -81,34 +124,155 @@ func CmdConfig(opts *Options) *cobra.Command {
 
 			profile := viper.GetString(flagProfile)
 
+			var (
+				secretFile = filepath.Join(home, pathDNSimple, fmt.Sprintf("%s.%s", profile, secretFileExt))
+				keyFile    = filepath.Join(home, pathDNSimple, secretKeyFileName)
+			)
+
 			cmd.Println(fmt.Sprintf("Configuring profile '%s'", profile))
-			cfg, ext, err := promptConfig(cfg)
+			lookup := func(baseURL, token string) ([]dnsimple.Account, *dnsimple.Account, error) {
+				return lookupAccounts(cmd.Context(), opts.createClient(baseURL, token))
+			}
+
+			cfg, ext, err := promptConfig(cmd.OutOrStdout(), cfg, secretFile, keyFile, lookup)
+			if err != nil {
+				return err
+			}
+
+			cfgPath := filepath.Join(home, pathDNSimple, fmt.Sprintf("%s.%s", profile, strings.ToLower(ext)))
+
+			return writeProfileConfig(cfg, cfgPath)
+		},
+	}, opts)
+
+	cmd.AddCommand(CmdConfigGet(opts))
+	cmd.AddCommand(CmdConfigInit(opts))
+	cmd.AddCommand(CmdConfigProfile(opts))
+	cmd.AddCommand(CmdConfigSet(opts))
+	cmd.AddCommand(CmdConfigView(opts))
+
+	return cmd
+}
+
+func CmdConfigInit(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:   "init",
+		Short: "Create a profile without prompts",
+		Long: heredoc.Doc(`
+			Create a profile from flags and environment variables, without prompting.
+			Meant for CI jobs and containers where no terminal is available.
+
+			The access token is checked against the API before the profile is
+			written, and an existing profile is only replaced with --force.
+		`),
+		Args: cobra.NoArgs,
+		Example: heredoc.Doc(`
+			dnsimple config init --account 1234 --access-token $TOKEN --env sandbox --profile ci
+			DNSIMPLE_ACCOUNT=1234 DNSIMPLE_ACCESS_TOKEN=$TOKEN dnsimple config init --format json
+			dnsimple config init --account 1234 --token-command 'pass show dnsimple' --force
+		`),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if err := viper.BindPFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile := viper.GetString(flagProfile)
+			if err := validateProfileName(profile); err != nil {
+				return err
+			}
+
+			ext := strings.ToLower(viper.GetString(flagFormat))
+			if ext != configFormatJSON && ext != configFormatYAML && ext != configFormatTOML {
+				return fmt.Errorf("invalid file format %s", ext)
+			}
+
+			// Only flags and environment variables count, values of a
+			// profile being overwritten must not leak into the new one.
+			cfg := config.Config{
+				Account:      explicitValue(cmd, configAccount),
+				AccessToken:  explicitValue(cmd, configAccessToken),
+				TokenCommand: explicitValue(cmd, configTokenCommand),
+			}
+
+			switch env := strings.ToUpper(viper.GetString(flagEnv)); env {
+			case envProd:
+			case envSandbox:
+				cfg.Sandbox = true
+			case envDev:
+				cfg.BaseURL = explicitValue(cmd, configBaseURL)
+				if cfg.BaseURL == "" {
+					return errors.New("--base-url is required for the dev environment")
+				}
+			default:
+				return fmt.Errorf("invalid environment %s", viper.GetString(flagEnv))
+			}
+
+			if cfg.AccessToken != "" && cfg.TokenCommand != "" {
+				return errors.New("--access-token and --token-command cannot be used together")
+			}
+
+			validated := cfg
+			if err := validated.ResolveAccessToken(); err != nil {
+				return err
+			}
+
+			if validated.AccessToken == "" {
+				return errors.New("access token is required")
+			}
+
+			paths, err := configPaths()
 			if err != nil {
 				return err
 			}
 
-			v := viper.New()
-			v.Set(configAccount, cfg.Account)
-			v.Set(configAccessToken, cfg.AccessToken)
-			if cfg.BaseURL != "" {
-				v.Set(configBaseURL, cfg.BaseURL)
+			existing, err := findProfile(profile)
+			if err == nil && filepath.Dir(existing.Path) == paths[0] {
+				if !viper.GetBool(flagForce) {
+					return fmt.Errorf("profile %s already exists at %s, use --force to overwrite it", profile, existing.Path)
+				}
+			} else {
+				existing = nil
 			}
 
-			if cfg.Sandbox {
-				v.Set(flagSandbox, cfg.Sandbox)
+			baseURL := validated.BaseURL
+			if validated.Sandbox {
+				baseURL = sandboxBaseURL
 			}
 
-			cfgPath := filepath.Join(home, pathDNSimple, fmt.Sprintf("%s.%s", profile, strings.ToLower(ext)))
-			if err := v.WriteConfigAs(cfgPath); err != nil {
+			accounts, own, err := lookupAccounts(cmd.Context(), opts.createClient(baseURL, validated.AccessToken))
+			if err != nil {
+				return fmt.Errorf("could not validate access token: %w", err)
+			}
+
+			cfg.Account, err = selectAccount(cfg.Account, accounts, own)
+			if err != nil {
+				return err
+			}
+
+			cfgPath := filepath.Join(paths[0], fmt.Sprintf("%s.%s", profile, ext))
+
+			// An existing profile may use another file format.
+			if existing != nil && existing.Path != cfgPath {
+				if err := os.Remove(existing.Path); err != nil {
+					return err
+				}
+			}
+
+			if err := writeProfileConfig(&cfg, cfgPath); err != nil {
 				return err
 			}
 
+			cmd.Printf("%s Saved profile %s to %s\n", color.GreenString("✓"), profile, cfgPath)
+
 			return nil
 		},
 	}, opts)
 
-	cmd.AddCommand(CmdConfigGet(opts))
-	cmd.AddCommand(CmdConfigSet(opts))
+	cmd.Flags().String(flagEnv, strings.ToLower(envProd), "Environment: prod, sandbox or dev")
+	cmd.Flags().String(flagFormat, configFormatYAML, "File format: json, yaml or toml")
+	cmd.Flags().String(configTokenCommand, "", "Command that prints the access token")
+	cmd.Flags().Bool(flagForce, false, "Overwrite an existing profile")
 
 	return cmd
 }
@@ -152,6 +316,14 @@ func CmdConfigSet(opts *Options) *cobra.Command {
 				return err
 			}
 
+			// Profiles that keep the token in an encrypted file must never
+			// get it written to the profile file in clear text.
+			if secretFile := viper.GetString(configSecretFile); args[0] == configAccessToken && secretFile != "" {
+				backend := config.EncryptedFileBackend{Path: secretFile, KeyFile: viper.GetString(configKeyFile)}
+
+				return backend.Store(args[1])
+			}
+
 			viper.Set(args[0], value)
 
 			if err := viper.WriteConfig(); err != nil {
@@ -164,3 +336,202 @@ func CmdConfigSet(opts *Options) *cobra.Command {
 
 	return cmd
 }
+
+func CmdConfigView(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:   "view",
+		Short: "Show the effective configuration and where each value comes from",
+		Long: heredoc.Doc(`
+			Show the configuration obtained by merging flags, DNSIMPLE_* environment
+			variables, the profile file and defaults, in that order of precedence,
+			together with the source of each value.
+
+			Secrets such as the access token are redacted unless --show-secrets is
+			given.
+		`),
+		Args: cobra.NoArgs,
+		Example: heredoc.Doc(`
+			dnsimple config view
+			dnsimple config view --profile sandbox --output json
+		`),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if err := viper.BindPFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output := viper.GetString(flagOutput)
+			if output != formatTable && output != formatJSON && output != formatYAML {
+				return errors.New("invalid output format")
+			}
+
+			view := format.ConfigView{
+				{Key: flagProfile, Value: activeProfile, Source: activeProfileSource},
+				{Key: configConfigFile, Value: viper.ConfigFileUsed(), Source: configFileSource()},
+			}
+
+			for _, key := range configViewKeys {
+				value, source := configValueSource(cmd, key)
+
+				if _, ok := configSecrets[key]; ok && value != "" && !viper.GetBool(flagShowSecrets) {
+					value = redacted
+				}
+
+				view = append(view, format.ConfigValue{Key: key, Value: value, Source: source})
+			}
+
+			formattedOutput, err := format.Format(view, &format.Options{
+				Format: format.OutputFormat(output),
+				// TODO: query should be only used for JSON and YAML output formats
+				Query: viper.GetString(flagQuery),
+			})
+			if err != nil {
+				return err
+			}
+
+			if _, err := io.Copy(cmd.OutOrStdout(), formattedOutput); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}, opts)
+
+	cmd.Flags().Bool(flagShowSecrets, false, "Show secrets instead of redacting them")
+	addOutputFlag(cmd, formatTable, formatJSON, formatYAML)
+	addQueryFlag(cmd)
+
+	return cmd
+}
+
+// configValueSource returns the effective value of key and where it comes
+// from, following the precedence viper applies.
+func configValueSource(cmd *cobra.Command, key string) (string, string) {
+	value := viper.GetString(key)
+
+	// The sandbox setting wins over any base URL, see config.NewWithValidation.
+	if key == configBaseURL && viper.GetBool(flagSandbox) {
+		return sandboxBaseURL, "sandbox setting"
+	}
+
+	if f := cmd.Flag(key); f != nil && f.Changed {
+		return value, "--" + key + " flag"
+	}
+
+	// Like viper, ignore variables set to an empty value.
+	if env := envName(key); os.Getenv(env) != "" {
+		return value, "environment variable " + env
+	}
+
+	if viper.InConfig(key) {
+		return value, "profile file"
+	}
+
+	if key == configBaseURL {
+		return prodBaseURL, "default"
+	}
+
+	if value == "" {
+		return value, "unset"
+	}
+
+	return value, "default"
+}
+
+// explicitValue returns the value given for key by a flag or an environment
+// variable, ignoring profile files and defaults.
+func explicitValue(cmd *cobra.Command, key string) string {
+	if f := cmd.Flag(key); f != nil && f.Changed {
+		return f.Value.String()
+	}
+
+	return os.Getenv(envName(key))
+}
+
+func envName(key string) string {
+	return envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+func configFileSource() string {
+	switch {
+	case configFile != "":
+		return "--" + configConfigFile + " flag"
+	case os.Getenv(envDNSimpleConfigFile) != "":
+		return "environment variable " + envDNSimpleConfigFile
+	case viper.ConfigFileUsed() == "":
+		return "not found"
+	default:
+		return "profile " + activeProfile
+	}
+}
+
+// selectAccount picks the account of a profile created without prompts. An
+// account email is resolved to its ID, and the account is detected when the
+// token belongs to a single account.
+func selectAccount(value string, accounts []dnsimple.Account, own *dnsimple.Account) (string, error) {
+	if value == "" && own != nil {
+		return strconv.FormatInt(own.ID, 10), nil
+	}
+
+	if value == "" && len(accounts) == 1 {
+		return strconv.FormatInt(accounts[0].ID, 10), nil
+	}
+
+	if value == "" {
+		emails := make([]string, 0, len(accounts))
+		for _, account := range accounts {
+			emails = append(emails, fmt.Sprintf("%d (%s)", account.ID, account.Email))
+		}
+
+		return "", fmt.Errorf("--account is required, the token has access to: %s", strings.Join(emails, ", "))
+	}
+
+	for _, account := range accounts {
+		id := strconv.FormatInt(account.ID, 10)
+		if value == id || strings.EqualFold(value, account.Email) {
+			return id, nil
+		}
+	}
+
+	return "", fmt.Errorf("the access token has no access to account %s", value)
+}
+
+// writeProfileConfig writes cfg to the profile file at path. An access token
+// is stored in the secret file when one is configured, and in the profile
+// file otherwise.
+func writeProfileConfig(cfg *config.Config, path string) error {
+	v := viper.New()
+	v.SetConfigPermissions(0o600)
+	v.Set(configAccount, cfg.Account)
+
+	switch {
+	case cfg.TokenCommand != "":
+		v.Set(configTokenCommand, cfg.TokenCommand)
+	case cfg.SecretFile != "":
+		backend := config.EncryptedFileBackend{Path: cfg.SecretFile, KeyFile: cfg.KeyFile}
+		if err := backend.Store(cfg.AccessToken); err != nil {
+			return err
+		}
+
+		v.Set(configSecretFile, cfg.SecretFile)
+		if cfg.KeyFile != "" {
+			v.Set(configKeyFile, cfg.KeyFile)
+		}
+	default:
+		v.Set(configAccessToken, cfg.AccessToken)
+	}
+
+	if cfg.BaseURL != "" {
+		v.Set(configBaseURL, cfg.BaseURL)
+	}
+
+	if cfg.Sandbox {
+		v.Set(flagSandbox, cfg.Sandbox)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	return v.WriteConfigAs(path)
+}
@@ -0,0 +1,326 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/edsonmichaque/dnsimple-cli/internal/format"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func CmdConfigProfile(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "profile",
+		Short:   "Manage profiles",
+		Aliases: []string{"profiles"},
+	}
+
+	cmd.AddCommand(CmdConfigProfileCopy(opts))
+	cmd.AddCommand(CmdConfigProfileCurrent(opts))
+	cmd.AddCommand(CmdConfigProfileDelete(opts))
+	cmd.AddCommand(CmdConfigProfileList(opts))
+	cmd.AddCommand(CmdConfigProfileRename(opts))
+	cmd.AddCommand(CmdConfigProfileUse(opts))
+
+	return cmd
+}
+
+func CmdConfigProfileList(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:   actionList,
+		Short: "List profiles",
+		Args:  cobra.NoArgs,
+		Example: heredoc.Doc(`
+			dnsimple config profile list
+			dnsimple config profile list --output json
+		`),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if err := viper.BindPFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output := viper.GetString(flagOutput)
+			if output != formatTable && output != formatJSON && output != formatYAML {
+				return errors.New("invalid output format")
+			}
+
+			profiles, err := listProfiles()
+			if err != nil {
+				return err
+			}
+
+			formattedOutput, err := format.Format(format.ProfileList(profiles), &format.Options{
+				Format: format.OutputFormat(output),
+				// TODO: query should be only used for JSON and YAML output formats
+				Query: viper.GetString(flagQuery),
+			})
+			if err != nil {
+				return err
+			}
+
+			if _, err := io.Copy(cmd.OutOrStdout(), formattedOutput); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}, opts)
+
+	addOutputFlag(cmd, formatTable, formatJSON, formatYAML)
+	addQueryFlag(cmd)
+
+	return cmd
+}
+
+func CmdConfigProfileCurrent(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:   "current",
+		Short: "Show the active profile and what selected it",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.Printf("%s (selected by %s)\n", activeProfile, activeProfileSource)
+
+			return nil
+		},
+	}, opts)
+
+	return cmd
+}
+
+func CmdConfigProfileUse(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:               "use NAME",
+		Short:             "Make a profile the current profile",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeProfileArgs(1),
+		Example: heredoc.Doc(`
+			dnsimple config profile use sandbox
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := findProfile(args[0]); err != nil {
+				return err
+			}
+
+			if err := writeCurrentProfile(args[0]); err != nil {
+				return err
+			}
+
+			cmd.Printf("%s Now using profile %s\n", color.GreenString("✓"), args[0])
+
+			if os.Getenv(envDNSimpleProfile) != "" {
+				cmd.PrintErrf("%s is set and takes precedence over the current profile\n", envDNSimpleProfile)
+			}
+
+			return nil
+		},
+	}, opts)
+
+	return cmd
+}
+
+func CmdConfigProfileCopy(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:               "copy SOURCE DESTINATION",
+		Short:             "Copy a profile",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeProfileArgs(1),
+		Example: heredoc.Doc(`
+			dnsimple config profile copy default staging
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			src, dst, err := profileTransferPaths(args[0], args[1])
+			if err != nil {
+				return err
+			}
+
+			data, err := os.ReadFile(src)
+			if err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+				return err
+			}
+
+			if err := os.WriteFile(dst, data, 0o600); err != nil {
+				return err
+			}
+
+			if err := transferSecretFile(args[0], filepath.Dir(src), args[1], dst, false); err != nil {
+				return err
+			}
+
+			cmd.Printf("%s Copied profile %s to %s\n", color.GreenString("✓"), args[0], args[1])
+
+			return nil
+		},
+	}, opts)
+
+	return cmd
+}
+
+func CmdConfigProfileRename(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:               "rename SOURCE DESTINATION",
+		Short:             "Rename a profile",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeProfileArgs(1),
+		Example: heredoc.Doc(`
+			dnsimple config profile rename staging sandbox
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			src, dst, err := profileTransferPaths(args[0], args[1])
+			if err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+				return err
+			}
+
+			if err := os.Rename(src, dst); err != nil {
+				return err
+			}
+
+			if err := transferSecretFile(args[0], filepath.Dir(src), args[1], dst, true); err != nil {
+				return err
+			}
+
+			if current, _ := readCurrentProfile(); current == args[0] {
+				if err := writeCurrentProfile(args[1]); err != nil {
+					return err
+				}
+			}
+
+			cmd.Printf("%s Renamed profile %s to %s\n", color.GreenString("✓"), args[0], args[1])
+
+			return nil
+		},
+	}, opts)
+
+	return cmd
+}
+
+func CmdConfigProfileDelete(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:               "delete NAME",
+		Short:             "Delete a profile",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeProfileArgs(1),
+		Example: heredoc.Doc(`
+			dnsimple config profile delete staging
+			dnsimple config profile delete staging --confirm
+		`),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if err := viper.BindPFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := findProfile(args[0])
+			if err != nil {
+				return err
+			}
+
+			if !viper.GetBool(configConfirm) {
+				confirmation, err := promptConfirmation(fmt.Sprintf("Do you want to delete profile %s?", args[0]), false)
+				if err != nil {
+					return err
+				}
+
+				if !confirmation {
+					return errors.New("no confirmation")
+				}
+			}
+
+			// The encrypted token written by "config" for this profile is
+			// useless without it, unless a copy made before copies got a
+			// secret file of their own still uses it.
+			v, err := readProfileFile(p.Path)
+			if err != nil {
+				return err
+			}
+
+			if secretFile := v.GetString(configSecretFile); secretFile == profileSecretFile(filepath.Dir(p.Path), args[0]) {
+				if err := removeSecretFile(secretFile, args[0]); err != nil {
+					return err
+				}
+			}
+
+			if err := os.Remove(p.Path); err != nil {
+				return err
+			}
+
+			if current, _ := readCurrentProfile(); current == args[0] {
+				if err := writeCurrentProfile(""); err != nil {
+					return err
+				}
+			}
+
+			cmd.Printf("%s Deleted profile %s\n", color.GreenString("✓"), args[0])
+
+			return nil
+		},
+	}, opts)
+
+	addConfirmFlag(cmd)
+
+	return cmd
+}
+
+// profileTransferPaths returns the file of the source profile and the file
+// the destination profile would be written to, refusing to overwrite an
+// existing profile.
+func profileTransferPaths(srcName, dstName string) (string, string, error) {
+	if err := validateProfileName(dstName); err != nil {
+		return "", "", err
+	}
+
+	src, err := findProfile(srcName)
+	if err != nil {
+		return "", "", err
+	}
+
+	if _, err := findProfile(dstName); err == nil {
+		return "", "", fmt.Errorf("profile %s already exists", dstName)
+	}
+
+	paths, err := configPaths()
+	if err != nil {
+		return "", "", err
+	}
+
+	return src.Path, filepath.Join(paths[0], dstName+filepath.Ext(src.Path)), nil
+}
+
+func validateProfileName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) || name == pathCurrentProfile {
+		return fmt.Errorf("invalid profile name %q", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/edsonmichaque/dnsimple-cli/internal/cmdtest"
+	"github.com/edsonmichaque/dnsimple-cli/internal/config"
+	"github.com/spf13/viper"
+)
+
+const profileToken = "encrypted-test-token"
+
+func TestConfigProfileCopyThenDelete(t *testing.T) {
+	server := cmdtest.NewServer(t)
+
+	// Run once so that the configuration directory of the test is set.
+	cmdtest.Run(t, server, "config", "profile", "list")
+	dir := writeEncryptedProfile(t, "default")
+
+	for _, args := range [][]string{
+		{"config", "profile", "copy", "default", "staging"},
+		{"config", "profile", "delete", "default", "--confirm"},
+	} {
+		if res := cmdtest.Run(t, server, args...); res.Err != nil {
+			t.Fatalf("%v: %v\n%s", args, res.Err, res.Stderr)
+		}
+	}
+
+	assertProfileToken(t, dir, "staging")
+}
+
+func TestConfigProfileRename(t *testing.T) {
+	server := cmdtest.NewServer(t)
+
+	cmdtest.Run(t, server, "config", "profile", "list")
+	dir := writeEncryptedProfile(t, "staging")
+
+	if res := cmdtest.Run(t, server, "config", "profile", "rename", "staging", "production"); res.Err != nil {
+		t.Fatalf("%v\n%s", res.Err, res.Stderr)
+	}
+
+	assertProfileToken(t, dir, "production")
+
+	if _, err := os.Stat(filepath.Join(dir, "staging.secret")); !os.IsNotExist(err) {
+		t.Errorf("the secret file of the old name is still there: %v", err)
+	}
+}
+
+func TestConfigProfileDeleteKeepsSharedSecret(t *testing.T) {
+	server := cmdtest.NewServer(t)
+
+	cmdtest.Run(t, server, "config", "profile", "list")
+	dir := writeEncryptedProfile(t, "default")
+
+	// A copy made by an older version shares the secret file.
+	data, err := os.ReadFile(filepath.Join(dir, "default.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "legacy.yaml"), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if res := cmdtest.Run(t, server, "config", "profile", "delete", "default", "--confirm"); res.Err != nil {
+		t.Fatalf("%v\n%s", res.Err, res.Stderr)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "default.secret")); err != nil {
+		t.Errorf("the secret file used by another profile was removed: %v", err)
+	}
+}
+
+// writeEncryptedProfile writes the profile name with its access token in an
+// encrypted secret file, and returns the directory of the profiles.
+func writeEncryptedProfile(t *testing.T, name string) string {
+	t.Helper()
+
+	dir := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "dnsimple")
+	backend := config.EncryptedFileBackend{
+		Path:    filepath.Join(dir, name+".secret"),
+		KeyFile: filepath.Join(dir, "secret.key"),
+	}
+
+	if err := backend.Store(profileToken); err != nil {
+		t.Fatal(err)
+	}
+
+	v := viper.New()
+	v.Set("account", "1010")
+	v.Set("secret-file", backend.Path)
+	v.Set("key-file", backend.KeyFile)
+
+	if err := v.WriteConfigAs(filepath.Join(dir, name+".yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+// assertProfileToken checks that the profile name uses a secret file of its
+// own holding the access token.
+func assertProfileToken(t *testing.T, dir, name string) {
+	t.Helper()
+
+	v := viper.New()
+	v.SetConfigFile(filepath.Join(dir, name+".yaml"))
+
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := v.GetString("secret-file"), filepath.Join(dir, name+".secret"); got != want {
+		t.Errorf("profile %s uses secret file %s, want %s", name, got, want)
+	}
+
+	token, err := config.EncryptedFileBackend{Path: v.GetString("secret-file"), KeyFile: v.GetString("key-file")}.Token()
+	if err != nil {
+		t.Fatalf("profile %s: %v", name, err)
+	}
+
+	if token != profileToken {
+		t.Errorf("profile %s has token %q, want %q", name, token, profileToken)
+	}
+}
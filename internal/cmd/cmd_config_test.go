@@ -0,0 +1,209 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/cmd"
+	"github.com/edsonmichaque/dnsimple-cli/internal/cmdtest"
+	"github.com/edsonmichaque/dnsimple-cli/internal/fakeapi"
+	"github.com/spf13/viper"
+)
+
+func TestConfigView(t *testing.T) {
+	server := cmdtest.NewServer(t)
+
+	t.Setenv("DNSIMPLE_KEY_FILE", "/etc/dnsimple/secret.key")
+
+	// The profile file and the base URL are fixed so that the columns of
+	// the table do not depend on the test.
+	view := []string{"config", "view", "-c", "testdata/fixtures/config-view.yaml", "--base-url", cmdtest.BaseURL}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "config_view", args: view},
+		{name: "config_view_json", args: append(view, "-o", "json")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := cmdtest.Run(t, server, tt.args...)
+			if res.Err != nil {
+				t.Fatalf("%v\n%s", res.Err, res.Stderr)
+			}
+
+			for _, token := range []string{cmdtest.Token, "profile-token"} {
+				if strings.Contains(res.Stdout, token) {
+					t.Errorf("the access token %s is printed:\n%s", token, res.Stdout)
+				}
+			}
+
+			cmdtest.AssertGolden(t, tt.name, res.Stdout)
+		})
+	}
+
+	res := cmdtest.Run(t, server, append(view, "--show-secrets")...)
+	if !strings.Contains(res.Stdout, cmdtest.Token) {
+		t.Errorf("--show-secrets does not show the access token:\n%s", res.Stdout)
+	}
+}
+
+func TestConfigInitEnvironments(t *testing.T) {
+	tests := []struct {
+		env         string
+		wantSandbox bool
+		wantBaseURL bool
+	}{
+		{env: "prod"},
+		{env: "sandbox", wantSandbox: true},
+		{env: "dev", wantBaseURL: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			server := newInitServer(t)
+
+			res := cmdtest.Run(t, server, "config", "init", "--env", tt.env)
+			if res.Err != nil {
+				t.Fatalf("%v\n%s", res.Err, res.Stderr)
+			}
+
+			v := readProfile(t, "default.yaml")
+
+			if got := v.GetBool("sandbox"); got != tt.wantSandbox {
+				t.Errorf("sandbox = %t, want %t", got, tt.wantSandbox)
+			}
+
+			wantBaseURL := ""
+			if tt.wantBaseURL {
+				wantBaseURL = server.URL
+			}
+
+			if got := v.GetString("base-url"); got != wantBaseURL {
+				t.Errorf("base-url = %q, want %q", got, wantBaseURL)
+			}
+
+			if got := v.GetString("account"); got != cmdtest.Account {
+				t.Errorf("account = %q, want %q", got, cmdtest.Account)
+			}
+		})
+	}
+
+	res := cmdtest.Run(t, newInitServer(t), "config", "init", "--env", "staging")
+	if res.Err == nil {
+		t.Error("an unknown environment was accepted")
+	}
+}
+
+func TestConfigInitFormats(t *testing.T) {
+	for _, format := range []string{"json", "yaml", "toml"} {
+		t.Run(format, func(t *testing.T) {
+			res := cmdtest.Run(t, newInitServer(t), "config", "init", "--format", format)
+			if res.Err != nil {
+				t.Fatalf("%v\n%s", res.Err, res.Stderr)
+			}
+
+			v := readProfile(t, "default."+format)
+
+			if got := v.GetString("access-token"); got != cmdtest.Token {
+				t.Errorf("access-token = %q, want %q", got, cmdtest.Token)
+			}
+		})
+	}
+}
+
+func TestConfigInitOverwrite(t *testing.T) {
+	server := newInitServer(t)
+
+	if res := cmdtest.Run(t, server, "config", "init"); res.Err != nil {
+		t.Fatalf("%v\n%s", res.Err, res.Stderr)
+	}
+
+	res := cmdtest.Run(t, server, "config", "init", "--access-token", "other-token")
+	if res.Err == nil || !strings.Contains(res.Err.Error(), "--force") {
+		t.Fatalf("got error %v, want the profile to be kept without --force", res.Err)
+	}
+
+	if got := readProfile(t, "default.yaml").GetString("access-token"); got != cmdtest.Token {
+		t.Errorf("access-token = %q after a refused overwrite, want %q", got, cmdtest.Token)
+	}
+
+	res = cmdtest.Run(t, server, "config", "init", "--access-token", "other-token", "--format", "json", "--force")
+	if res.Err != nil {
+		t.Fatalf("%v\n%s", res.Err, res.Stderr)
+	}
+
+	if got := readProfile(t, "default.json").GetString("access-token"); got != "other-token" {
+		t.Errorf("access-token = %q after --force, want other-token", got)
+	}
+
+	if _, err := os.Stat(profilePath("default.yaml")); !os.IsNotExist(err) {
+		t.Errorf("the profile in the old format is still there: %v", err)
+	}
+}
+
+func TestConfigInitRejectedToken(t *testing.T) {
+	server := cmdtest.NewServer(t)
+	server.Handle(http.MethodGet, "/v2/whoami", http.StatusUnauthorized, `{"message":"Authentication failed"}`)
+
+	res := cmdtest.Run(t, server, "config", "init")
+	if res.ExitCode != cmd.ExitAuth {
+		t.Errorf("exit code %d, want %d (%v)", res.ExitCode, cmd.ExitAuth, res.Err)
+	}
+
+	cmdtest.AssertGolden(t, "config_init_rejected", res.Stderr)
+
+	if entries, _ := os.ReadDir(filepath.Dir(profilePath(""))); len(entries) != 0 {
+		t.Errorf("wrote %d file(s) for a rejected token", len(entries))
+	}
+}
+
+func newInitServer(t *testing.T) *cmdtest.Server {
+	t.Helper()
+
+	server := cmdtest.NewServer(t)
+	server.Fallback = fakeapi.New(dnsimple.Account{ID: 1010, Email: "ops@example.com"})
+
+	return server
+}
+
+// profilePath returns the path of a file in the profile directory of the
+// test that ran a command last.
+func profilePath(name string) string {
+	return filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "dnsimple", name)
+}
+
+func readProfile(t *testing.T, name string) *viper.Viper {
+	t.Helper()
+
+	v := viper.New()
+	v.SetConfigFile(profilePath(name))
+
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	return v
+}
@@ -0,0 +1,130 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/fakeapi"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const defaultFakeServerPort = 8089
+
+func CmdDev(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:   "dev",
+		Short: "Development tools",
+	}, opts)
+
+	cmd.AddCommand(CmdDevFakeServer(opts))
+
+	return cmd
+}
+
+func CmdDevFakeServer(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:   "fake-server",
+		Short: "Run an in-memory fake of the DNSimple API",
+		Long: heredoc.Doc(`
+			Run an in-memory fake of the parts of the DNSimple API v2 used by the
+			CLI: accounts, whoami, domains, collaborators, delegation signer records,
+			DNSSEC, zones and records.
+
+			Any access token is accepted and the state is lost when the server stops.
+			Point the CLI at it with --base-url. The OAuth flow of "auth login" is
+			granted right away.
+		`),
+		Args: cobra.NoArgs,
+		Example: heredoc.Doc(`
+			dnsimple dev fake-server --port 8089 --domain example.com --domain example.net
+			dnsimple domain list --base-url http://127.0.0.1:8089 --access-token fake --account 1
+		`),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if err := viper.BindPFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			accountID := viper.GetInt64(flagAccountID)
+
+			server := fakeapi.New(dnsimple.Account{
+				ID:             accountID,
+				Email:          viper.GetString(flagEmail),
+				PlanIdentifier: "teams-v1-monthly",
+			})
+
+			for _, name := range viper.GetStringSlice(configDomain) {
+				if _, err := server.AddDomain(accountID, name); err != nil {
+					return err
+				}
+			}
+
+			listener, err := net.Listen("tcp", net.JoinHostPort(viper.GetString(flagHost), strconv.Itoa(viper.GetInt(flagPort))))
+			if err != nil {
+				return err
+			}
+
+			httpServer := &http.Server{
+				Handler:           server,
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+
+			cmd.Printf("Fake DNSimple API listening on http://%s (account %d)\n", listener.Addr(), accountID)
+			cmd.Printf("Use it with --base-url http://%s --access-token fake --account %d\n", listener.Addr(), accountID)
+
+			errc := make(chan error, 1)
+			go func() {
+				errc <- httpServer.Serve(listener)
+			}()
+
+			select {
+			case err := <-errc:
+				return err
+			case <-cmd.Context().Done():
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err := httpServer.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("stopping the server: %w", err)
+			}
+
+			cmd.Println("Stopped")
+
+			return nil
+		},
+	}, opts)
+
+	cmd.Flags().String(flagHost, "127.0.0.1", "Address to listen on")
+	cmd.Flags().Int(flagPort, defaultFakeServerPort, "Port to listen on")
+	cmd.Flags().Int64(flagAccountID, 1, "ID of the account")
+	cmd.Flags().String(flagEmail, "dev@example.com", "Email of the account")
+	cmd.Flags().StringSlice(configDomain, nil, "Domain created at startup, can be repeated")
+
+	return cmd
+}
@@ -18,16 +18,17 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"io"
+	"sort"
+	"sync"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/api"
 	"github.com/edsonmichaque/dnsimple-cli/internal/config"
 	"github.com/edsonmichaque/dnsimple-cli/internal/format"
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -59,7 +60,13 @@ func CmdDomainList(opts *Options) *cobra.Command {
 		Example: heredoc.Doc(`
 			dnsimple domain list
 			dnsimple domain list --sandbox
+			dnsimple domain list --all-accounts
+			dnsimple domain list --account 1234,5678
 		`),
+		Annotations: map[string]string{
+			annotationCacheable:    "true",
+			annotationMultiAccount: "true",
+		},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			if err := viper.BindPFlags(cmd.Flags()); err != nil {
 				panic(err)
@@ -73,150 +80,93 @@ func CmdDomainList(opts *Options) *cobra.Command {
 
 			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
 
-			resp, err := apiClient.Domains.ListDomains(context.Background(), cfg.Account, &dnsimple.DomainListOptions{
-				ListOptions: getListOptions(),
-			})
-			if err != nil {
-				return err
-			}
-
-			output := viper.GetString(flagOutput)
-			if output != formatTable && output != formatJSON && output != formatYAML {
-				return errors.New("invalid output format" + output)
-			}
-
-			formattedOutput, err := format.Format(format.DomainList(*resp), &format.Options{
-				Format: format.OutputFormat(output),
-				// TODO: query should be only used for JSON and YAML output formats
-				Query: viper.GetString(flagQuery),
-			})
+			accounts, err := targetAccounts(cmd.Context(), apiClient, cfg)
 			if err != nil {
 				return err
 			}
 
-			if _, err := io.Copy(cmd.OutOrStdout(), formattedOutput); err != nil {
-				return err
+			// Pages are per account, so with several accounts every page of
+			// each of them is fetched instead.
+			fanOut := len(accounts) > 1
+			if fanOut && (cmd.Flags().Changed(optPage) || cmd.Flags().Changed(optPerPage)) {
+				return &usageError{err: errors.New("--page and --per-page cannot be used with several accounts"), commandPath: cmd.CommandPath()}
 			}
 
-			return nil
-		},
-	}, opts)
-
-	addOutputFlag(cmd, formatTable)
-	addPaginationFlags(cmd)
-	addQueryFlag(cmd)
+			var (
+				mu   sync.Mutex
+				resp *dnsimple.DomainsResponse
+			)
 
-	return cmd
-}
+			err = forEachAccount(accounts, func(account string) error {
+				if !fanOut {
+					accountResp, err := apiClient.Domains.ListDomains(cmd.Context(), account, &dnsimple.DomainListOptions{
+						ListOptions: getListOptions(),
+					})
+					if err != nil {
+						return err
+					}
 
-func CmdDomainDelete(opts *Options) *cobra.Command {
-	cmd := createCmd(&cobra.Command{
-		Use:   "delete",
-		Short: "Delete a domain",
-		Args:  cobra.NoArgs,
-		Example: heredoc.Doc(`
-			dnsimple domain delete --domain example.com
-			dnsimple domain delete --domain example.com --sandbox
-		`),
-		PreRun: func(cmd *cobra.Command, args []string) {
-			if err := viper.BindPFlags(cmd.Flags()); err != nil {
-				panic(err)
-			}
-		},
-		RunE: func(cmd *cobra.Command, args []string) error {
-			confirm := viper.GetBool(configConfirm)
+					resp = accountResp
 
-			if !confirm {
-				if !runConfirm(viper.GetString(configDomain)) {
-					return errors.New("no confirmation")
+					return nil
 				}
-			}
 
-			cfg, err := config.New()
-			if err != nil {
-				return err
-			}
-
-			domain := viper.GetString(configDomain)
-
-			if domain == "" {
-				domain, err = runPromptDomainName()
+				domains, err := listAllDomains(cmd.Context(), apiClient, account)
 				if err != nil {
-					return nil
+					return err
 				}
-			}
 
-			_, err = opts.createClient(cfg.BaseURL, cfg.AccessToken).Domains.DeleteDomain(
-				context.Background(),
-				cfg.Account,
-				domain)
-			if err != nil {
-				return err
-			}
+				mu.Lock()
+				defer mu.Unlock()
 
-			cmd.Printf("✓ Deleted domain %s", domain)
-
-			return nil
-		},
-	}, opts)
+				if resp == nil {
+					resp = &dnsimple.DomainsResponse{}
+				}
 
-	addDomainFlag(cmd)
-	addConfirmFlag(cmd)
+				resp.Data = append(resp.Data, domains...)
 
-	return cmd
-}
-
-func CmdDomainCreate(opts *Options) *cobra.Command {
-	cmd := createCmd(&cobra.Command{
-		Use:   actionCreate,
-		Short: "Create a domain",
-		Example: heredoc.Doc(`
-			dnsimple domain create --domain example.com
-			dnsimple domain create --domain example.com --sandbox
-		`),
-		Args: cobra.MaximumNArgs(1),
-		PreRun: func(cmd *cobra.Command, args []string) {
-			if err := viper.BindPFlags(cmd.Flags()); err != nil {
-				panic(err)
-			}
-		},
-		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg, err := config.New()
+				return nil
+			})
 			if err != nil {
 				return err
 			}
 
-			var domain dnsimple.Domain
-			if len(args) != 0 {
-				err = json.Unmarshal([]byte(args[0]), &domain)
-				if err != nil {
-					return err
-				}
+			if resp == nil {
+				resp = &dnsimple.DomainsResponse{Data: []dnsimple.Domain{}}
 			}
 
-			if domain == (dnsimple.Domain{}) {
-				domain.Name, err = runPromptDomainName()
-				if err != nil {
-					return nil
-				}
+			if fanOut {
+				sort.SliceStable(resp.Data, func(i, j int) bool {
+					return resp.Data[i].AccountID < resp.Data[j].AccountID
+				})
 			}
 
-			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
+			output := viper.GetString(flagOutput)
+			if output != formatTable && output != formatJSON && output != formatYAML {
+				return errors.New("invalid output format" + output)
+			}
 
-			resp, err := apiClient.Domains.CreateDomain(
-				context.Background(),
-				cfg.Account,
-				domain)
+			formattedOutput, err := format.Format(format.DomainList(*resp), &format.Options{
+				Format: format.OutputFormat(output),
+				// TODO: query should be only used for JSON and YAML output formats
+				Query: viper.GetString(flagQuery),
+			})
 			if err != nil {
 				return err
 			}
 
-			cmd.Printf("%s Created domain %s\n", color.GreenString("✓"), resp.Data.Name)
+			if _, err := io.Copy(cmd.OutOrStdout(), formattedOutput); err != nil {
+				return err
+			}
 
 			return nil
 		},
 	}, opts)
 
+	addOutputFlag(cmd, formatTable, formatJSON, formatYAML)
+	addPaginationFlags(cmd)
+	addQueryFlag(cmd)
+
 	return cmd
 }
 
@@ -229,6 +179,7 @@ func CmdDomainGet(opts *Options) *cobra.Command {
 			dnsimple domain show --domain example.com
 			dnsimple domain show --domain example.com --sandbox
 		`),
+		Annotations: map[string]string{annotationCacheable: "true"},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			if err := viper.BindPFlags(cmd.Flags()); err != nil {
 				panic(err)
@@ -242,7 +193,7 @@ func CmdDomainGet(opts *Options) *cobra.Command {
 
 			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
 			resp, err := apiClient.Domains.GetDomain(
-				context.Background(),
+				cmd.Context(),
 				cfg.Account,
 				viper.GetString(configDomain),
 			)
@@ -272,17 +223,16 @@ func CmdDomainGet(opts *Options) *cobra.Command {
 		},
 	}, opts)
 
+	addDomainFlag(cmd)
 	addQueryFlag(cmd)
-	addOutputFlag(cmd, formatText)
+	addOutputFlag(cmd, formatText, formatJSON, formatYAML)
 
 	return cmd
 }
 
 func addDomainFlag(cmd *cobra.Command) {
-	cmd.Flags().String(configDomain, "", "Domain flags")
-	if err := cmd.MarkFlagRequired("domain"); err != nil {
-		panic(err)
-	}
+	cmd.Flags().String(configDomain, "", "Domain name")
+	markFlagPicked(cmd.Flags(), configDomain)
 }
 
 func addConfirmFlag(cmd *cobra.Command) {
@@ -327,3 +277,23 @@ func getListOptionsP() *dnsimple.ListOptions {
 
 	return &opts
 }
+
+// listAllDomains fetches every domain of the account, following pagination.
+func listAllDomains(ctx context.Context, client *api.Client, account string) ([]dnsimple.Domain, error) {
+	var items []dnsimple.Domain
+
+	for page := 1; ; page++ {
+		resp, err := client.Domains.ListDomains(ctx, account, &dnsimple.DomainListOptions{
+			ListOptions: dnsimple.ListOptions{Page: dnsimple.Int(page)},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, resp.Data...)
+
+		if resp.Pagination == nil || page >= resp.Pagination.TotalPages {
+			return items, nil
+		}
+	}
+}
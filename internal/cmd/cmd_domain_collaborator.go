@@ -27,6 +27,7 @@ import (
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/api"
 	"github.com/edsonmichaque/dnsimple-cli/internal/config"
 	"github.com/edsonmichaque/dnsimple-cli/internal/format"
 	"github.com/spf13/cobra"
@@ -113,8 +114,8 @@ func CmdCollaboratorAdd(opts *Options) *cobra.Command {
 
 			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
 
-			resp, err := apiClient.Domains.AddCollaborator(
-				context.Background(),
+			resp, err := apiClient.Collaborators.AddCollaborator(
+				cmd.Context(),
 				cfg.Account,
 				domain,
 				attr,
@@ -157,8 +158,8 @@ func CmdCollaboratorRemove(opts *Options) *cobra.Command {
 
 			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
 
-			_, err = apiClient.Domains.RemoveCollaborator(
-				context.Background(),
+			_, err = apiClient.Collaborators.RemoveCollaborator(
+				cmd.Context(),
 				cfg.Account,
 				domain,
 				viper.GetInt64(configCollaboratorID),
@@ -181,9 +182,7 @@ func CmdCollaboratorRemove(opts *Options) *cobra.Command {
 
 func addCollaboratorIDFlag(cmd *cobra.Command) {
 	cmd.Flags().Int64(configCollaboratorID, 0, "Collaborator id")
-	if err := cmd.MarkFlagRequired(configCollaboratorID); err != nil {
-		panic(err)
-	}
+	markFlagPicked(cmd.Flags(), configCollaboratorID)
 }
 
 func applyOpts(cmd *cobra.Command, opts *Options) {
@@ -207,8 +206,9 @@ func CmdCollaboratorList(opts *Options) *cobra.Command {
 			dnsimple collaborator list --domain example.com
 			dnsimple collaborator list --domain example.com
 		`),
-		Args:  cobra.NoArgs,
-		Short: "List collaborators",
+		Args:        cobra.NoArgs,
+		Short:       "List collaborators",
+		Annotations: map[string]string{annotationCacheable: "true"},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			if err := viper.BindPFlags(cmd.Flags()); err != nil {
 				panic(err)
@@ -224,7 +224,7 @@ func CmdCollaboratorList(opts *Options) *cobra.Command {
 
 			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
 
-			resp, err := apiClient.Domains.ListCollaborators(context.Background(), cfg.Account, domain, nil)
+			resp, err := apiClient.Collaborators.ListCollaborators(cmd.Context(), cfg.Account, domain, nil)
 			if err != nil {
 				return err
 			}
@@ -252,7 +252,7 @@ func CmdCollaboratorList(opts *Options) *cobra.Command {
 	}, opts)
 
 	addQueryFlag(cmd)
-	addOutputFlag(cmd, formatTable)
+	addOutputFlag(cmd, formatTable, formatJSON, formatYAML)
 	addPaginationFlags(cmd)
 
 	return cmd
@@ -260,11 +260,27 @@ func CmdCollaboratorList(opts *Options) *cobra.Command {
 
 func addDomainRequiredFlag(cmd *cobra.Command) {
 	cmd.PersistentFlags().String(configDomain, "", "Domain name")
-	if err := cmd.MarkPersistentFlagRequired(configDomain); err != nil {
-		panic(err)
-	}
+	markFlagPicked(cmd.PersistentFlags(), configDomain)
 }
 
 func addFromFileFlag(cmd *cobra.Command) {
 	cmd.Flags().StringP(optionFromFile, "f", "", "Create from file")
 }
+
+// listAllCollaborators fetches every collaborator of a domain, following pagination.
+func listAllCollaborators(ctx context.Context, client *api.Client, account, domain string) ([]dnsimple.Collaborator, error) {
+	var items []dnsimple.Collaborator
+
+	for page := 1; ; page++ {
+		resp, err := client.Collaborators.ListCollaborators(ctx, account, domain, &dnsimple.ListOptions{Page: dnsimple.Int(page)})
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, resp.Data...)
+
+		if resp.Pagination == nil || page >= resp.Pagination.TotalPages {
+			return items, nil
+		}
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/api"
+	"github.com/edsonmichaque/dnsimple-cli/internal/config"
+	"github.com/edsonmichaque/dnsimple-cli/internal/format"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func CmdDomainCreate(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:   actionCreate + " [JSON]",
+		Short: "Create a domain",
+		Long: heredoc.Doc(`
+			Create a domain named with --domain, or described by a JSON object given
+			as argument or read with --from-file. --domain takes precedence over the
+			name in the JSON object.
+
+			With --bulk, create every domain listed in a file, one name per line,
+			running at most --concurrency requests at once. Blank lines and lines
+			starting with # are ignored. A domain that fails to be created does not
+			stop the others, and the outcome of each one is printed once they are
+			all processed.
+
+			With --template, the template is applied to every domain created.
+
+			With --output json or yaml, the created domain is printed, or the outcome
+			of each domain with --bulk.
+		`),
+		Args: cobra.MaximumNArgs(1),
+		Example: heredoc.Doc(`
+			dnsimple domain create --domain example.com
+			dnsimple domain create --domain example.com --template web-hosting
+			dnsimple domain create --from-file domain.json
+			dnsimple domain create --bulk domains.txt --template web-hosting
+			dnsimple domain create --domain example.com --output json
+			cat domains.txt | dnsimple domain create --bulk - --output json
+		`),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if err := viper.BindPFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.New()
+			if err != nil {
+				return err
+			}
+
+			output := viper.GetString(flagOutput)
+			if output != formatTable && output != formatJSON && output != formatYAML {
+				return errors.New("invalid output format")
+			}
+
+			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
+			template := viper.GetString(flagTemplate)
+
+			if path := viper.GetString(flagBulk); path != "" {
+				if len(args) != 0 {
+					return &usageError{err: errors.New("--bulk does not take a JSON argument"), commandPath: cmd.CommandPath()}
+				}
+
+				return runBulkDomainCreate(cmd, apiClient, cfg.Account, path, template)
+			}
+
+			domain, err := domainAttributes(cmd, opts, args)
+			if err != nil {
+				return err
+			}
+
+			resp, err := apiClient.Domains.CreateDomain(cmd.Context(), cfg.Account, domain)
+			if err != nil {
+				return err
+			}
+
+			// The progress is only reported in tables, so that JSON and YAML
+			// output holds the created domain alone.
+			report := func(msg string, a ...interface{}) {
+				if output == formatTable {
+					cmd.Printf("%s "+msg+"\n", append([]interface{}{color.GreenString("✓")}, a...)...)
+				}
+			}
+
+			report("Created domain %s", resp.Data.Name)
+
+			if template != "" {
+				if _, err := apiClient.Templates.ApplyTemplate(cmd.Context(), cfg.Account, template, resp.Data.Name); err != nil {
+					return fmt.Errorf("applying template %s to %s: %w", template, resp.Data.Name, err)
+				}
+
+				report("Applied template %s to %s", template, resp.Data.Name)
+			}
+
+			if output == formatTable {
+				return nil
+			}
+
+			formattedOutput, err := format.Format(format.DomainItem(*resp), &format.Options{
+				Format: format.OutputFormat(output),
+				Query:  viper.GetString(flagQuery),
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = io.Copy(cmd.OutOrStdout(), formattedOutput)
+
+			return err
+		},
+	}, opts)
+
+	cmd.Flags().String(configDomain, "", "Name of the domain")
+	cmd.Flags().String(flagTemplate, "", "ID or short name of a template to apply to the domain")
+	cmd.Flags().String(flagBulk, "", `File listing the names of the domains to create, one per line, or "-" for stdin`)
+	addFromFileFlag(cmd)
+	addConcurrencyFlag(cmd)
+	addOutputFlag(cmd, formatTable, formatJSON, formatYAML)
+	addQueryFlag(cmd)
+
+	cmd.MarkFlagsMutuallyExclusive(flagBulk, configDomain)
+	cmd.MarkFlagsMutuallyExclusive(flagBulk, optionFromFile)
+
+	return cmd
+}
+
+// domainAttributes returns the domain described by the JSON argument or the
+// --from-file file, named after --domain if set. The name is asked for when
+// missing on a terminal.
+func domainAttributes(cmd *cobra.Command, opts *Options, args []string) (dnsimple.Domain, error) {
+	var (
+		domain   dnsimple.Domain
+		body     []byte
+		err      error
+		fromFile = viper.GetString(optionFromFile)
+	)
+
+	switch {
+	case len(args) != 0:
+		body = []byte(args[0])
+	case fromFile == "-":
+		body, err = io.ReadAll(cmd.InOrStdin())
+	case fromFile != "":
+		body, err = os.ReadFile(fromFile)
+	}
+
+	if err != nil {
+		return domain, err
+	}
+
+	if len(body) != 0 {
+		if err := json.Unmarshal(body, &domain); err != nil {
+			return domain, fmt.Errorf("invalid domain: %w", err)
+		}
+	}
+
+	if name := viper.GetString(configDomain); name != "" {
+		domain.Name = name
+	}
+
+	if domain.Name != "" {
+		return domain, nil
+	}
+
+	if !isTerminal(opts.Stdin) || !isTerminal(opts.Stdout) {
+		return domain, &usageError{err: errors.New(`required flag "--domain" not set`), commandPath: cmd.CommandPath()}
+	}
+
+	domain.Name, err = runPromptDomainName()
+
+	return domain, err
+}
+
+func runBulkDomainCreate(cmd *cobra.Command, client *api.Client, account, path, template string) error {
+	names, err := readDomainNames(cmd, path)
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		return fmt.Errorf("no domain names in %s", path)
+	}
+
+	results, failed := createDomains(cmd.Context(), client, account, names, template, viper.GetInt(flagConcurrency))
+
+	formattedOutput, err := format.Format(results, &format.Options{
+		Format: format.OutputFormat(viper.GetString(flagOutput)),
+		// TODO: query should be only used for JSON and YAML output formats
+		Query: viper.GetString(flagQuery),
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(cmd.OutOrStdout(), formattedOutput); err != nil {
+		return err
+	}
+
+	if err := cmd.Context().Err(); err != nil {
+		return fmt.Errorf("interrupted after creating %d of %d domain(s): %w", countDomainStatus(results, "created"), len(names), err)
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("%d of %d domain(s) failed", failed, len(names))
+	}
+
+	return nil
+}
+
+// readDomainNames reads the names listed in path, or in stdin for "-", one
+// per line. Blank lines, comments and repeated names are skipped.
+func readDomainNames(cmd *cobra.Command, path string) ([]string, error) {
+	r := cmd.InOrStdin()
+
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		r = f
+	}
+
+	var (
+		names   []string
+		seen    = make(map[string]struct{})
+		scanner = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		name := strings.TrimSpace(scanner.Text())
+		if name == "" || strings.HasPrefix(name, "#") {
+			continue
+		}
+
+		if _, ok := seen[strings.ToLower(name)]; ok {
+			continue
+		}
+
+		seen[strings.ToLower(name)] = struct{}{}
+		names = append(names, name)
+	}
+
+	return names, scanner.Err()
+}
+
+// createDomains creates a domain for every name, applying template to those
+// created when set, running at most concurrency requests at once. It
+// returns the outcome for each name, in the same order, and the number of
+// names that failed.
+func createDomains(
+	ctx context.Context,
+	client *api.Client,
+	account string,
+	names []string,
+	template string,
+	concurrency int,
+) (format.DomainCreateResultList, int) {
+	var (
+		mu      sync.Mutex
+		failed  int
+		results = make(format.DomainCreateResultList, len(names))
+	)
+
+	fail := func(result *format.DomainCreateResult, err error) {
+		result.Error = err.Error()
+
+		mu.Lock()
+		failed++
+		mu.Unlock()
+	}
+
+	runConcurrently(len(names), concurrency, func(i int) {
+		result := &results[i]
+		result.Domain = names[i]
+
+		// Domains not started yet are skipped once the command is interrupted.
+		if ctx.Err() != nil {
+			result.Status = "skipped"
+
+			return
+		}
+
+		if _, err := client.Domains.CreateDomain(ctx, account, dnsimple.Domain{Name: names[i]}); err != nil {
+			result.Status = "failed"
+			fail(result, err)
+
+			return
+		}
+
+		result.Status = "created"
+
+		if template == "" {
+			return
+		}
+
+		if _, err := client.Templates.ApplyTemplate(ctx, account, template, names[i]); err != nil {
+			result.Template = "failed"
+			fail(result, err)
+
+			return
+		}
+
+		result.Template = "applied"
+	})
+
+	return results, failed
+}
+
+func countDomainStatus(results format.DomainCreateResultList, status string) int {
+	n := 0
+
+	for _, result := range results {
+		if result.Status == status {
+			n++
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,83 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/edsonmichaque/dnsimple-cli/internal/cmdtest"
+)
+
+func TestDomainCreateBulk(t *testing.T) {
+	server := newServer(t)
+	server.Handle(http.MethodPost, "/v2/1010/domains", http.StatusCreated, `{"data":{"id":1,"name":"example.org"}}`)
+	server.Handle(http.MethodPost, "/v2/1010/domains/example.org/templates/web", http.StatusCreated, `{"data":{}}`)
+
+	input := "example.org\n# parked\n\nexample.io\nexample.org\n"
+
+	res := cmdtest.RunWithInput(t, server, input,
+		"domain", "create",
+		"--bulk", "-",
+		"--template", "web",
+		"--concurrency", "1",
+	)
+
+	if res.Err == nil {
+		t.Fatal("expected the failed template to be reported")
+	}
+
+	cmdtest.AssertGolden(t, "domain_create_bulk", res.Stdout)
+
+	var created []string
+
+	for _, req := range server.Requests() {
+		if req.Method == http.MethodPost && req.Path == "/v2/1010/domains" {
+			created = append(created, req.Body)
+		}
+	}
+
+	if len(created) != 2 {
+		t.Errorf("created %v, want example.org and example.io once each", created)
+	}
+}
+
+func TestDomainCreateOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+	}{
+		{name: "domain_create", output: "table"},
+		{name: "domain_create_json", output: "json"},
+		{name: "domain_create_yaml", output: "yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newServer(t)
+			server.Handle(http.MethodPost, "/v2/1010/domains", http.StatusCreated, `{"data":{"id":1,"account_id":1010,"name":"example.org","state":"hosted"}}`)
+			server.Handle(http.MethodPost, "/v2/1010/domains/example.org/templates/web", http.StatusCreated, `{"data":{}}`)
+
+			res := cmdtest.Run(t, server, "domain", "create", "--domain", "example.org", "--template", "web", "--output", tt.output)
+			if res.Err != nil {
+				t.Fatalf("%v\n%s", res.Err, res.Stderr)
+			}
+
+			cmdtest.AssertGolden(t, tt.name, res.Stdout)
+		})
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/api"
+	"github.com/edsonmichaque/dnsimple-cli/internal/config"
+	"github.com/edsonmichaque/dnsimple-cli/internal/format"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func CmdDomainDelete(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:   actionDelete,
+		Short: "Delete domains",
+		Long: heredoc.Doc(`
+			Delete the domain named with --domain, every domain listed in a file
+			with --from-file, one name per line, or every domain whose name matches
+			the --match pattern, such as "staging-*".
+
+			The domains are listed before anything is deleted, and the deletion must
+			be confirmed by typing the name of the domain, or the number of domains
+			when there are several, unless --confirm is set.
+
+			A snapshot of the zone of each domain is written to --dir before it is
+			deleted, and a domain whose zone could not be backed up is not deleted.
+			It can be restored with "zone restore".
+
+			Registered domains that have not expired are refused unless
+			--force-registered is set.
+		`),
+		Args: cobra.NoArgs,
+		Example: heredoc.Doc(`
+			dnsimple domain delete --domain example.com
+			dnsimple domain delete --from-file domains.txt --dir ./backups
+			dnsimple domain delete --match 'staging-*' --confirm
+		`),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if err := viper.BindPFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.New()
+			if err != nil {
+				return err
+			}
+
+			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
+
+			domains, err := deletionTargets(cmd, apiClient, cfg.Account)
+			if err != nil {
+				return err
+			}
+
+			if len(domains) == 0 {
+				cmd.Printf("%s No domain matches %s\n", color.GreenString("✓"), viper.GetString(flagMatch))
+
+				return nil
+			}
+
+			if !viper.GetBool(flagForceRegistered) {
+				if err := checkRegistrations(domains, time.Now()); err != nil {
+					return err
+				}
+			}
+
+			plan, err := format.Format(format.DomainDeletionList(domains), &format.Options{Format: format.OutputFormatTable})
+			if err != nil {
+				return err
+			}
+
+			if _, err := io.Copy(cmd.OutOrStdout(), plan); err != nil {
+				return err
+			}
+
+			if !viper.GetBool(configConfirm) {
+				if err := confirmDeletion(cmd, opts, domains); err != nil {
+					return err
+				}
+			}
+
+			return deleteDomains(cmd, apiClient, cfg.Account, domains)
+		},
+	}, opts)
+
+	cmd.Flags().String(configDomain, "", "Domain name")
+	cmd.Flags().String(optionFromFile, "", `File listing the names of the domains to delete, one per line, or "-" for stdin`)
+	cmd.Flags().String(flagMatch, "", `Delete the domains whose name matches this pattern, such as "staging-*"`)
+	cmd.Flags().String(flagDir, ".", "Directory where the zones are backed up before deleting")
+	cmd.Flags().Bool(flagForceRegistered, false, "Delete registered domains that have not expired")
+	addConfirmFlag(cmd)
+	addConcurrencyFlag(cmd)
+
+	markFlagPicked(cmd.Flags(), configDomain, optionFromFile, flagMatch)
+	cmd.MarkFlagsMutuallyExclusive(configDomain, optionFromFile, flagMatch)
+
+	return cmd
+}
+
+// deletionTargets returns the domains named with --domain, listed in the
+// --from-file file or matching the --match pattern. Every named domain must
+// exist.
+func deletionTargets(cmd *cobra.Command, client *api.Client, account string) ([]dnsimple.Domain, error) {
+	if pattern := viper.GetString(flagMatch); pattern != "" {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, &usageError{err: fmt.Errorf("invalid pattern %q: %w", pattern, err), commandPath: cmd.CommandPath()}
+		}
+
+		domains, err := listAllDomains(cmd.Context(), client, account)
+		if err != nil {
+			return nil, err
+		}
+
+		var matched []dnsimple.Domain
+
+		for _, domain := range domains {
+			if ok, _ := path.Match(pattern, domain.Name); ok {
+				matched = append(matched, domain)
+			}
+		}
+
+		return matched, nil
+	}
+
+	names := []string{viper.GetString(configDomain)}
+
+	if fromFile := viper.GetString(optionFromFile); fromFile != "" {
+		var err error
+
+		names, err = readDomainNames(cmd, fromFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(names) == 0 {
+			return nil, fmt.Errorf("no domain names in %s", fromFile)
+		}
+	}
+
+	domains := make([]dnsimple.Domain, len(names))
+
+	err := runConcurrentlyE(len(names), viper.GetInt(flagConcurrency), func(i int) error {
+		resp, err := client.Domains.GetDomain(cmd.Context(), account, names[i])
+		if err != nil {
+			return fmt.Errorf("domain %s: %w", names[i], err)
+		}
+
+		domains[i] = *resp.Data
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domains, nil
+}
+
+// checkRegistrations fails when any of domains is registered and has not
+// expired at now. A registration whose expiry cannot be read is assumed not
+// to have expired.
+func checkRegistrations(domains []dnsimple.Domain, now time.Time) error {
+	var registered []string
+
+	for _, domain := range domains {
+		if domain.State != "registered" {
+			continue
+		}
+
+		expiresAt, err := time.Parse(time.RFC3339, domain.ExpiresAt)
+		if err != nil || expiresAt.After(now) {
+			registered = append(registered, domain.Name)
+		}
+	}
+
+	if len(registered) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"refusing to delete %d registered domain(s) that have not expired: %s; set --force-registered to delete them anyway",
+		len(registered),
+		strings.Join(registered, ", "),
+	)
+}
+
+// confirmDeletion asks the user to type the name of the domain, or the number
+// of domains when there are several.
+func confirmDeletion(cmd *cobra.Command, opts *Options, domains []dnsimple.Domain) error {
+	if !isTerminal(opts.Stdin) || !isTerminal(opts.Stdout) {
+		return &usageError{err: errors.New("--confirm is required when not running in a terminal"), commandPath: cmd.CommandPath()}
+	}
+
+	msg, want := fmt.Sprintf("Delete domain %s?", domains[0].Name), domains[0].Name
+	if len(domains) > 1 {
+		msg, want = fmt.Sprintf("Delete %d domains?", len(domains)), strconv.Itoa(len(domains))
+	}
+
+	confirmation, err := promptTypedConfirmation(msg, want)
+	if err != nil {
+		return err
+	}
+
+	if !confirmation {
+		return errors.New("no confirmation")
+	}
+
+	return nil
+}
+
+// deleteDomains backs up the zone of each domain into --dir and deletes the
+// domains whose zone was backed up, running at most --concurrency of them at
+// once.
+func deleteDomains(cmd *cobra.Command, client *api.Client, account string, domains []dnsimple.Domain) error {
+	var (
+		mu      sync.Mutex
+		deleted int
+		failed  int
+		dir     = viper.GetString(flagDir)
+	)
+
+	fail := func(msg string, a ...interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		failed++
+		cmd.Printf("%s "+msg+"\n", append([]interface{}{color.RedString("✗")}, a...)...)
+	}
+
+	runConcurrently(len(domains), viper.GetInt(flagConcurrency), func(i int) {
+		name := domains[i].Name
+
+		// Domains not started yet are skipped once the command is interrupted.
+		if cmd.Context().Err() != nil {
+			return
+		}
+
+		backup, err := deleteDomain(cmd.Context(), client, account, name, dir)
+		if err != nil {
+			fail("Failed %v", err)
+
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		deleted++
+		cmd.Printf("%s Deleted domain %s, zone backed up to %s\n", color.GreenString("✓"), name, backup)
+	})
+
+	if err := cmd.Context().Err(); err != nil {
+		return fmt.Errorf("interrupted after deleting %d of %d domain(s): %w", deleted, len(domains), err)
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("%d of %d domain(s) failed", failed, len(domains))
+	}
+
+	return nil
+}
+
+// deleteDomain backs up the zone of the domain name into dir and deletes the
+// domain, unless the backup failed. It returns the path of the backup.
+func deleteDomain(ctx context.Context, client *api.Client, account, name, dir string) (string, error) {
+	backup, err := backupZone(ctx, client, account, name, dir)
+	if err != nil {
+		return "", fmt.Errorf("backing up zone %s, not deleting the domain: %w", name, err)
+	}
+
+	if _, err := client.Domains.DeleteDomain(ctx, account, name); err != nil {
+		return "", fmt.Errorf("deleting domain %s: %w", name, err)
+	}
+
+	return backup, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/edsonmichaque/dnsimple-cli/internal/cmdtest"
+)
+
+func TestDomainDeleteMatch(t *testing.T) {
+	server := newServer(t)
+	server.Handle(http.MethodGet, "/v2/1010/domains/example.com", http.StatusOK, `{"data":{"id":181984,"name":"example.com","state":"registered"}}`)
+	server.Handle(http.MethodGet, "/v2/1010/domains/example.net", http.StatusOK, `{"data":{"id":181985,"name":"example.net","state":"hosted"}}`)
+	server.Handle(http.MethodDelete, "/v2/1010/domains/example.com", http.StatusNoContent, "")
+	server.Handle(http.MethodDelete, "/v2/1010/domains/example.net", http.StatusNoContent, "")
+
+	dir := t.TempDir()
+
+	res := cmdtest.Run(t, server, "domain", "delete", "--match", "example.*", "--confirm", "--dir", dir)
+	if res.Err == nil || !strings.Contains(res.Err.Error(), "example.com") {
+		t.Fatalf("got error %v, want example.com refused as registered", res.Err)
+	}
+
+	if n := countDeletes(server); n != 0 {
+		t.Fatalf("deleted %d domain(s) without --force-registered", n)
+	}
+
+	res = cmdtest.Run(t, server,
+		"domain", "delete",
+		"--match", "example.*",
+		"--force-registered",
+		"--confirm",
+		"--dir", dir,
+		"--concurrency", "1",
+	)
+	if res.Err != nil {
+		t.Fatalf("%v\n%s", res.Err, res.Stderr)
+	}
+
+	snapshot := regexp.MustCompile(`-\d{8}T\d{6}\.\d{3}Z\.json`)
+	cmdtest.AssertGolden(t, "domain_delete_match", snapshot.ReplaceAllString(strings.ReplaceAll(res.Stdout, dir, "BACKUPS"), "-TIMESTAMP.json"))
+
+	backups, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(backups) != 2 || countDeletes(server) != 2 {
+		t.Errorf("wrote %d backup(s) and deleted %d domain(s), want 2 of each", len(backups), countDeletes(server))
+	}
+}
+
+func TestDomainDeleteRequiresConfirmation(t *testing.T) {
+	server := newServer(t)
+	server.Handle(http.MethodGet, "/v2/1010/domains/example.net", http.StatusOK, `{"data":{"id":181985,"name":"example.net","state":"hosted"}}`)
+
+	res := cmdtest.Run(t, server, "domain", "delete", "--domain", "example.net", "--dir", t.TempDir())
+	if res.ExitCode != 2 {
+		t.Errorf("exit code %d, want 2: %v", res.ExitCode, res.Err)
+	}
+
+	if n := countDeletes(server); n != 0 {
+		t.Errorf("deleted %d domain(s) without confirmation", n)
+	}
+}
+
+func countDeletes(server *cmdtest.Server) int {
+	n := 0
+
+	for _, req := range server.Requests() {
+		if req.Method == http.MethodDelete {
+			n++
+		}
+	}
+
+	return n
+}
@@ -17,8 +17,6 @@
 package cmd
 
 import (
-	"context"
-
 	"github.com/edsonmichaque/dnsimple-cli/internal/config"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -42,9 +40,10 @@ func CmdDomainDNSSec(opts *Options) *cobra.Command {
 
 func CmdDnssecStatus(opts *Options) *cobra.Command {
 	cmd := createCmd(&cobra.Command{
-		Use:   "status",
-		Short: "Retrieve DNSSEC status",
-		Args:  cobra.NoArgs,
+		Use:         "status",
+		Short:       "Retrieve DNSSEC status",
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{annotationCacheable: "true"},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			if err := viper.BindPFlags(cmd.Flags()); err != nil {
 				panic(err)
@@ -60,8 +59,8 @@ func CmdDnssecStatus(opts *Options) *cobra.Command {
 
 			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
 
-			resp, err := apiClient.Domains.GetDnssec(
-				context.Background(),
+			resp, err := apiClient.DNSSEC.GetDnssec(
+				cmd.Context(),
 				cfg.Account,
 				domain,
 			)
@@ -103,8 +102,8 @@ func CmdDnssecDisable(opts *Options) *cobra.Command {
 
 			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
 
-			_, err = apiClient.Domains.DisableDnssec(
-				context.Background(),
+			_, err = apiClient.DNSSEC.DisableDnssec(
+				cmd.Context(),
 				cfg.Account,
 				domain,
 			)
@@ -141,8 +140,8 @@ func CmdDnssecEnable(opts *Options) *cobra.Command {
 
 			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
 
-			_, err = apiClient.Domains.EnableDnssec(
-				context.Background(),
+			_, err = apiClient.DNSSEC.EnableDnssec(
+				cmd.Context(),
 				cfg.Account,
 				domain,
 			)
@@ -25,6 +25,7 @@ import (
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/api"
 	"github.com/edsonmichaque/dnsimple-cli/internal/config"
 	"github.com/edsonmichaque/dnsimple-cli/internal/format"
 	"github.com/fatih/color"
@@ -114,8 +115,8 @@ func CmdDomainDSRCreate(opts *Options) *cobra.Command {
 
 			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
 
-			resp, err := apiClient.Domains.CreateDelegationSignerRecord(
-				context.Background(),
+			resp, err := apiClient.DelegationSignerRecords.CreateDelegationSignerRecord(
+				cmd.Context(),
 				cfg.Account,
 				domain,
 				attr,
@@ -137,9 +138,10 @@ func CmdDomainDSRCreate(opts *Options) *cobra.Command {
 
 func CmdDomainDSRList(opts *Options) *cobra.Command {
 	cmd := createCmd(&cobra.Command{
-		Use:   actionList,
-		Short: "List delegation signer records",
-		Args:  cobra.NoArgs,
+		Use:         actionList,
+		Short:       "List delegation signer records",
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{annotationCacheable: "true"},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			if err := viper.BindPFlags(cmd.Flags()); err != nil {
 				panic(err)
@@ -155,7 +157,7 @@ func CmdDomainDSRList(opts *Options) *cobra.Command {
 
 			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
 
-			resp, err := apiClient.Domains.ListDelegationSignerRecords(context.Background(), cfg.Account, domain, getListOptionsP())
+			resp, err := apiClient.DelegationSignerRecords.ListDelegationSignerRecords(cmd.Context(), cfg.Account, domain, getListOptionsP())
 			if err != nil {
 				return err
 			}
@@ -184,7 +186,7 @@ func CmdDomainDSRList(opts *Options) *cobra.Command {
 
 	addPaginationFlags(cmd)
 	addQueryFlag(cmd)
-	addOutputFlag(cmd, formatTable)
+	addOutputFlag(cmd, formatTable, formatJSON, formatYAML)
 
 	return cmd
 }
@@ -198,6 +200,7 @@ func CmdDomainDSRGet(opts *Options) *cobra.Command {
 			$ dnsimple dsr get --domain example.com --record-id 1
 			$ dnsimple dsr get --domain example.com --record-id 1 --sandbox
 		`),
+		Annotations: map[string]string{annotationCacheable: "true"},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			if err := viper.BindPFlags(cmd.Flags()); err != nil {
 				panic(err)
@@ -210,8 +213,8 @@ func CmdDomainDSRGet(opts *Options) *cobra.Command {
 			}
 
 			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
-			resp, err := apiClient.Domains.GetDelegationSignerRecord(
-				context.Background(),
+			resp, err := apiClient.DelegationSignerRecords.GetDelegationSignerRecord(
+				cmd.Context(),
 				cfg.Account,
 				viper.GetString(configDomain),
 				viper.GetInt64(flagRecordID),
@@ -244,14 +247,30 @@ func CmdDomainDSRGet(opts *Options) *cobra.Command {
 
 	addRecordIDFlag(cmd)
 	addQueryFlag(cmd)
-	addOutputFlag(cmd, formatText)
+	addOutputFlag(cmd, formatText, formatJSON, formatYAML)
 
 	return cmd
 }
 
 func addRecordIDFlag(cmd *cobra.Command) {
 	cmd.Flags().String(flagRecordID, "", "Record id")
-	if err := cmd.MarkFlagRequired("record-id"); err != nil {
-		panic(err)
+	markFlagPicked(cmd.Flags(), flagRecordID)
+}
+
+// listAllDelegationSignerRecords fetches every delegation signer record of a domain, following pagination.
+func listAllDelegationSignerRecords(ctx context.Context, client *api.Client, account, domain string) ([]dnsimple.DelegationSignerRecord, error) {
+	var items []dnsimple.DelegationSignerRecord
+
+	for page := 1; ; page++ {
+		resp, err := client.DelegationSignerRecords.ListDelegationSignerRecords(ctx, account, domain, &dnsimple.ListOptions{Page: dnsimple.Int(page)})
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, resp.Data...)
+
+		if resp.Pagination == nil || page >= resp.Pagination.TotalPages {
+			return items, nil
+		}
 	}
 }
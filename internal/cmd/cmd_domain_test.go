@@ -0,0 +1,68 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/cmd"
+	"github.com/edsonmichaque/dnsimple-cli/internal/cmdtest"
+	"github.com/edsonmichaque/dnsimple-cli/internal/fakeapi"
+)
+
+func TestDomainListAccountsFetchesEveryPage(t *testing.T) {
+	api := fakeapi.New(
+		dnsimple.Account{ID: 1010, Email: "ops@example.com"},
+		dnsimple.Account{ID: 2020, Email: "dev@example.com"},
+	)
+
+	// More than the 30 domains of the default page.
+	for i := 0; i < 35; i++ {
+		if _, err := api.AddDomain(1010, fmt.Sprintf("example-%02d.com", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := api.AddDomain(2020, "example.org"); err != nil {
+		t.Fatal(err)
+	}
+
+	server := cmdtest.NewServer(t)
+	server.Fallback = api
+
+	res := cmdtest.Run(t, server, "domain", "list", "--account", "1010,2020", "-o", "json")
+	if res.Err != nil {
+		t.Fatalf("%v\n%s", res.Err, res.Stderr)
+	}
+
+	var listed []dnsimple.Domain
+	if err := json.Unmarshal([]byte(res.Stdout), &listed); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(listed) != 36 {
+		t.Errorf("listed %d domain(s), want 36", len(listed))
+	}
+
+	res = cmdtest.Run(t, server, "domain", "list", "--account", "1010,2020", "--page", "2")
+	if res.ExitCode != cmd.ExitUsage {
+		t.Errorf("--page with several accounts: exit code %d, want %d (%v)", res.ExitCode, cmd.ExitUsage, res.Err)
+	}
+}
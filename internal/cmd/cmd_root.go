@@ -17,81 +17,272 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
 
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/edsonmichaque/dnsimple-cli/internal/config"
+	"github.com/edsonmichaque/dnsimple-cli/internal/transport"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
-	configFile string
-	profile    string
+	configFile     string
+	profile        string
+	initConfigOnce sync.Once
 )
 
+// cancelTimeoutKey is the context key of the function cancelling the
+// --timeout context of the executed command.
+type cancelTimeoutKey struct{}
+
 const (
+	annotationCacheable     = "cacheable"
+	annotationMultiAccount  = "multi-account"
+	annotationOutputFormats = "output-formats"
+	annotationPicked        = "picked"
 	binaryName              = "dnsimple"
 	configAccessToken       = "access-token"
 	configAccount           = "account"
 	configBaseURL           = "base-url"
+	configCacheTTL          = "cache-ttl"
+	configClientID          = "client-id"
+	configClientSecret      = "client-secret"
 	configCollaboratorID    = "collaborator-id"
 	configConfigFile        = "config-file"
 	configConfirm           = "confirm"
 	configDomain            = "domain"
+	configKeyFile           = "key-file"
+	configSecretFile        = "secret-file"
+	configTokenCommand      = "token-command"
 	defaultConfigFileFormat = "yaml"
 	defaultProfile          = "default"
 	envDNSimpleConfigFile   = "DNSIMPLE_CONFIG_FILE"
+	envDNSimplePassphrase   = "DNSIMPLE_PASSPHRASE"
 	envDNSimpleProfile      = "DNSIMPLE_PROFILE"
 	envDev                  = "DEV"
 	envPrefix               = "DNSIMPLE"
 	envProd                 = "PROD"
 	envSandbox              = "SANDBOX"
+	envXDGCacheHome         = "XDG_CACHE_HOME"
 	envXDGConfigHome        = "XDG_CONFIG_HOME"
+	flagAccountID           = "account-id"
+	flagAgainst             = "against"
+	flagAll                 = "all"
+	flagAllAccounts         = "all-accounts"
+	flagBulk                = "bulk"
+	flagConcurrency         = "concurrency"
+	flagContent             = "content"
+	flagDebugHTTP           = "debug-http"
+	flagDebugHTTPBody       = "debug-http-body"
+	flagDir                 = "dir"
+	flagDryRun              = "dry-run"
+	flagEmail               = "email"
+	flagEnv                 = "env"
+	flagFilter              = "filter"
+	flagForce               = "force"
+	flagFormat              = "format"
+	flagForceRegistered     = "force-registered"
+	flagFrom                = "from"
+	flagHost                = "host"
+	flagMatch               = "match"
+	flagMaxRetries          = "max-retries"
+	flagName                = "name"
+	flagNoCache             = "no-cache"
+	flagNoBrowser           = "no-browser"
 	flagOutput              = "output"
+	flagPort                = "port"
 	flagProfile             = "profile"
 	flagQuery               = "query"
+	flagRecordCassette      = "record-cassette"
 	flagRecordID            = "record-id"
+	flagReplayCassette      = "replay-cassette"
 	flagSandbox             = "sandbox"
+	flagShowSecrets         = "show-secrets"
+	flagTemplate            = "template"
+	flagTimeout             = "timeout"
+	flagTo                  = "to"
+	flagToAccount           = "to-account"
+	flagToProfile           = "to-profile"
+	flagType                = "type"
+	flagWait                = "wait"
+	flagZone                = "zone"
 	formatJSON              = "json"
 	formatTable             = "table"
 	formatText              = "text"
 	formatYAML              = "yaml"
+	keyProtectionKeyFile    = "key file"
+	keyProtectionPassphrase = "passphrase"
 	optPage                 = "page"
 	optPerPage              = "per-page"
 	optionFromFile          = "from-file"
 	pathConfigFile          = "/etc/dnsimple"
+	pathCurrentProfile      = "current-profile"
 	pathDNSimple            = "dnsimple"
+	secretFileExt           = "secret"
+	secretKeyFileName       = "secret.key"
+	tokenStorageCommand     = "command"
+	tokenStorageEncrypted   = "encrypted-file"
+	tokenStoragePlaintext   = "plaintext"
 )
 
+// Run executes the command line. The context of the commands is cancelled
+// on SIGINT or SIGTERM; a second signal terminates the process right away.
 func Run(opts *Options) error {
-	return CmdRoot(opts).Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	return Execute(ctx, opts, os.Args[1:])
+}
+
+// Execute runs the command line args with ctx and prints the error it fails
+// with, if any.
+func Execute(ctx context.Context, opts *Options, args []string) error {
+	resetCassette()
+
+	cacheReads = false
+	completing = false
+	commandStarted = false
+
+	cmd := CmdRoot(opts)
+	cmd.SetArgs(args)
+
+	executed, err := cmd.ExecuteContextC(ctx)
+	if executed != nil && executed.Context() != nil {
+		if cancel, ok := executed.Context().Value(cancelTimeoutKey{}).(context.CancelFunc); ok {
+			defer cancel()
+		}
+	}
+
+	// Until the command starts, errors come from cobra looking it up and
+	// validating its arguments, such as an unknown command.
+	if err != nil && !commandStarted {
+		err = newUsageError(executed, err)
+	}
+
+	if err != nil {
+		printError(cmd.ErrOrStderr(), err, viper.GetString(flagOutput))
+	}
+
+	return err
 }
 
+// commandStarted tells whether the running command got past the validation
+// of its arguments, see Execute.
+var commandStarted bool
+
 func CmdRoot(opts *Options) *cobra.Command {
 	cmd := createCmd(&cobra.Command{
-		Use:          binaryName,
-		SilenceUsage: true,
+		Use:   binaryName,
+		Short: "Manage DNSimple domains, zones and accounts",
+		Long: heredoc.Doc(`
+			Manage DNSimple domains, zones and accounts.
+
+			Exit codes:
+			  0    success
+			  1    other error
+			  2    invalid command line: unknown command, bad arguments or flags
+			  3    authentication or authorization failed (HTTP 401/403)
+			  4    resource not found (HTTP 404)
+			  5    invalid request (HTTP 400/409/422)
+			  6    rate limited (HTTP 429)
+			  7    server error (HTTP 5xx)
+			  8    network error
+			  124  timed out, see --timeout
+			  130  interrupted
+
+			Errors are printed to stderr, as a JSON object when the output format
+			is json.
+		`),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			commandStarted = true
+
+			// Cobra checks the flags only after the pre-run hooks, which
+			// would call the API and prompt for flags first.
+			if err := cmd.ValidateRequiredFlags(); err != nil {
+				return newUsageError(cmd, err)
+			}
+
+			if err := cmd.ValidateFlagGroups(); err != nil {
+				return newUsageError(cmd, err)
+			}
+
+			if isMultiAccount() && cmd.Annotations[annotationMultiAccount] == "" {
+				return fmt.Errorf("%s does not support multiple accounts", cmd.CommandPath())
+			}
+
+			cacheReads = cmd.Annotations[annotationCacheable] != ""
+
+			if timeout := viper.GetDuration(flagTimeout); timeout > 0 {
+				// Execute cancels the context once the command is done.
+				ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+				cmd.SetContext(context.WithValue(ctx, cancelTimeoutKey{}, cancel))
+			}
+
+			config.AccountResolver = accountResolver(cmd.Context(), opts)
+
+			return pickMissingFlags(cmd, opts)
+		},
 	}, opts)
 
 	cmd.AddCommand(CmdAccounts(opts))
+	cmd.AddCommand(CmdAuth(opts))
+	cmd.AddCommand(CmdCache(opts))
 	cmd.AddCommand(CmdConfig(opts))
+	cmd.AddCommand(CmdDev(opts))
 	cmd.AddCommand(CmdDomain(opts))
+	cmd.AddCommand(CmdUI(opts))
 	cmd.AddCommand(CmdVersion(opts))
 	cmd.AddCommand(CmdWhoami(opts))
+	cmd.AddCommand(CmdZone(opts))
 
-	cobra.OnInitialize(initConfig)
+	// The initializers of cobra are global, so that Execute can run several
+	// times in the same process.
+	initConfigOnce.Do(func() {
+		cobra.OnInitialize(initConfig)
+	})
+
+	config.PassphraseFunc = promptPassphrase
 
 	cmd.PersistentFlags().Bool(flagSandbox, false, "Sandbox environment")
 	cmd.PersistentFlags().String(configAccessToken, "", "Access token")
-	cmd.PersistentFlags().String(configAccount, "", "Account")
+	cmd.PersistentFlags().String(configAccount, "", "Account ID or email, or a comma separated list of them for read commands")
+	cmd.PersistentFlags().Bool(flagAllAccounts, false, "Run read commands against every account of the access token")
 	cmd.PersistentFlags().String(configBaseURL, "", "Base URL")
-	cmd.PersistentFlags().StringVar(&profile, flagProfile, defaultProfile, "Profile")
+	cmd.PersistentFlags().StringVar(&profile, flagProfile, "", `Profile (default is the current profile or "default")`)
 	cmd.PersistentFlags().StringVarP(&configFile, configConfigFile, "c", "", "Configuration file")
+	cmd.PersistentFlags().Int(flagMaxRetries, transport.DefaultMaxRetries, "Maximum number of retries of a failed API request")
+	cmd.PersistentFlags().Duration(flagTimeout, 0, "Time limit of the whole command, such as 30s or 5m, 0 for none")
+	cmd.PersistentFlags().Bool(flagDebugHTTP, false, "Log API requests and responses to stderr")
+	cmd.PersistentFlags().Bool(flagDebugHTTPBody, false, "Log headers and bodies too, implies --debug-http")
+	cmd.PersistentFlags().Duration(configCacheTTL, 0, "Cache the API responses of read commands for this long, such as 10m, 0 to disable")
+	cmd.PersistentFlags().Bool(flagNoCache, false, "Do not use cached API responses")
+	cmd.PersistentFlags().String(flagRecordCassette, "", "Record the API requests and responses to a cassette file, with credentials redacted")
+	cmd.PersistentFlags().String(flagReplayCassette, "", "Answer the API requests from a cassette file instead of the network")
 
 	cmd.MarkFlagsMutuallyExclusive(configBaseURL, flagSandbox)
+	cmd.MarkFlagsMutuallyExclusive(flagRecordCassette, flagReplayCassette)
+
+	registerCompletions(cmd, opts)
+
+	cmd.SetFlagErrorFunc(newUsageError)
 
 	viper.SetEnvPrefix(envPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
 	if err := viper.BindPFlags(cmd.PersistentFlags()); err != nil {
 		panic(err)
 	}
@@ -100,41 +291,90 @@ func CmdRoot(opts *Options) *cobra.Command {
 }
 
 func initConfig() {
-	var err error
+	activeProfile, activeProfileSource = resolveProfile()
+	viper.Set(flagProfile, activeProfile)
 
 	if configFile != "" {
 		viper.SetConfigFile(configFile)
 	} else if configFile := os.Getenv(envDNSimpleConfigFile); configFile != "" {
 		viper.SetConfigFile(configFile)
 	} else {
-		configHome := os.Getenv(envXDGConfigHome)
-		if configHome == "" {
-			configHome, err = os.UserConfigDir()
-			cobra.CheckErr(err)
+		paths, err := configPaths()
+		cobra.CheckErr(err)
+
+		for _, path := range paths {
+			viper.AddConfigPath(path)
 		}
 
-		viper.AddConfigPath(filepath.Join(configHome, pathDNSimple))
-		viper.AddConfigPath(pathConfigFile)
 		viper.SetConfigType(defaultConfigFileFormat)
+		viper.SetConfigName(activeProfile)
+	}
 
-		viper.SetConfigName(defaultProfile)
+	viper.AutomaticEnv()
 
-		if profileEnv := os.Getenv(envDNSimpleProfile); profileEnv != "" {
-			viper.SetConfigName(profileEnv)
+	if err := viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			fmt.Println("Found error: ", err.Error())
 		}
+	}
+}
+
+// configPaths returns the directories searched for profile files, in order
+// of precedence.
+func configPaths() ([]string, error) {
+	configHome := os.Getenv(envXDGConfigHome)
+	if configHome == "" {
+		var err error
 
-		if profile != "" {
-			viper.SetConfigName(profile)
+		configHome, err = os.UserConfigDir()
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	viper.AutomaticEnv()
+	return []string{filepath.Join(configHome, pathDNSimple), pathConfigFile}, nil
+}
 
-	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			fmt.Println("Found error: ", err.Error())
+// cacheDir returns the directory of the response cache of all profiles.
+func cacheDir() (string, error) {
+	cacheHome := os.Getenv(envXDGCacheHome)
+	if cacheHome == "" {
+		var err error
+
+		cacheHome, err = os.UserCacheDir()
+		if err != nil {
+			return "", err
 		}
 	}
+
+	return filepath.Join(cacheHome, pathDNSimple), nil
+}
+
+// loadProfile reads the named profile into a viper instance of its own, so
+// that it can be used alongside the active profile.
+func loadProfile(name string) (*viper.Viper, error) {
+	paths, err := configPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	v := viper.New()
+	for _, path := range paths {
+		v.AddConfigPath(path)
+	}
+
+	v.SetConfigType(defaultConfigFileFormat)
+	v.SetConfigName(name)
+
+	if err := v.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+			return nil, fmt.Errorf("profile %s not found", name)
+		}
+
+		return nil, err
+	}
+
+	return v, nil
 }
 
 func createCmd(cmd *cobra.Command, opts *Options) *cobra.Command {
@@ -0,0 +1,185 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/edsonmichaque/dnsimple-cli/internal/cmd"
+	"github.com/edsonmichaque/dnsimple-cli/internal/cmdtest"
+)
+
+func newServer(t *testing.T) *cmdtest.Server {
+	t.Helper()
+
+	server := cmdtest.NewServer(t)
+	server.HandleFile(http.MethodGet, "/v2/whoami", http.StatusOK, "testdata/fixtures/whoami.json")
+	server.HandleFile(http.MethodGet, "/v2/accounts", http.StatusOK, "testdata/fixtures/accounts.json")
+	server.HandleFile(http.MethodGet, "/v2/1010/domains", http.StatusOK, "testdata/fixtures/domains.json")
+	server.HandleFile(http.MethodGet, "/v2/1010/zones", http.StatusOK, "testdata/fixtures/zones.json")
+	server.HandleFile(http.MethodGet, "/v2/1010/zones/example.com/records", http.StatusOK, "testdata/fixtures/example.com-records.json")
+	server.HandleFile(http.MethodGet, "/v2/1010/zones/example.net/records", http.StatusOK, "testdata/fixtures/example.net-records.json")
+
+	return server
+}
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "whoami", args: []string{"whoami"}},
+		{name: "whoami_json", args: []string{"whoami", "-o", "json"}},
+		{name: "accounts", args: []string{"accounts"}},
+		{name: "domain_list", args: []string{"domain", "list"}},
+		{name: "domain_list_json", args: []string{"domain", "list", "-o", "json"}},
+		{name: "zone_record_search", args: []string{"zone", "record", "search", "--content", "203.0.113.10"}},
+		{name: "zone_record_search_type", args: []string{"zone", "record", "search", "--type", "MX", "-o", "text"}},
+		{name: "zone_copy_dry_run", args: []string{"zone", "copy", "--from", "example.com", "--to", "example.net", "--dry-run"}},
+		{name: "zone_diff", args: []string{"zone", "diff", "example.com", "example.net"}},
+		{name: "zone_diff_against", args: []string{"zone", "diff", "--domain", "example.com", "--against", "testdata/fixtures/example.com-snapshot.json"}},
+		{name: "zone_diff_against_json", args: []string{"zone", "diff", "--domain", "example.com", "--against", "testdata/fixtures/example.com-snapshot.json", "-o", "json"}},
+		{name: "complete_domain", args: []string{"__complete", "domain", "delete", "--domain", "example.n"}},
+		{name: "complete_output", args: []string{"__complete", "accounts", "-o", ""}},
+		{name: "complete_output_text", args: []string{"__complete", "whoami", "-o", ""}},
+		{name: "complete_output_search", args: []string{"__complete", "zone", "record", "search", "-o", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := cmdtest.Run(t, newServer(t), tt.args...)
+			if res.Err != nil {
+				t.Fatalf("unexpected error: %v\n%s", res.Err, res.Stderr)
+			}
+
+			cmdtest.AssertGolden(t, tt.name, res.Stdout)
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		setup    func(server *cmdtest.Server)
+		exitCode int
+	}{
+		{
+			name:     "error_not_found",
+			args:     []string{"zone", "diff", "example.com", "nope.com"},
+			exitCode: cmd.ExitNotFound,
+		},
+		{
+			name:     "error_not_found_json",
+			args:     []string{"zone", "diff", "example.com", "nope.com", "-o", "json"},
+			exitCode: cmd.ExitNotFound,
+		},
+		{
+			name:     "error_validation",
+			args:     []string{"domain", "create", `{"name":"-invalid-"}`},
+			exitCode: cmd.ExitValidation,
+		},
+		{
+			name: "error_unauthorized",
+			args: []string{"accounts"},
+			setup: func(server *cmdtest.Server) {
+				server.Handle(http.MethodGet, "/v2/accounts", http.StatusUnauthorized, `{"message":"Authentication failed"}`)
+			},
+			exitCode: cmd.ExitAuth,
+		},
+		{
+			name:     "error_usage",
+			args:     []string{"domain", "list", "--unknown"},
+			exitCode: cmd.ExitUsage,
+		},
+		{
+			name:     "error_replace_empty",
+			args:     []string{"zone", "record", "replace", "--from=", "--to", "198.51.100.7", "--confirm"},
+			exitCode: cmd.ExitUsage,
+		},
+		{
+			name:     "error_flag_required",
+			args:     []string{"domain", "dsr", "get", "--domain", "example.com"},
+			exitCode: cmd.ExitUsage,
+		},
+		{
+			name:     "error_required_flags",
+			args:     []string{"zone", "record", "replace"},
+			exitCode: cmd.ExitUsage,
+		},
+		{
+			name:     "error_extra_arg",
+			args:     []string{"domain", "get", "x"},
+			exitCode: cmd.ExitUsage,
+		},
+		{
+			name:     "error_unknown_command",
+			args:     []string{"nosuchcmd"},
+			exitCode: cmd.ExitUsage,
+		},
+		{
+			name:     "error_flag_group",
+			args:     []string{"accounts", "--sandbox", "--base-url", "http://127.0.0.1"},
+			exitCode: cmd.ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newServer(t)
+			server.HandleFile(http.MethodGet, "/v2/1010/zones/nope.com/records", http.StatusNotFound, "testdata/fixtures/domain-not-found.json")
+			server.HandleFile(http.MethodPost, "/v2/1010/domains", http.StatusBadRequest, "testdata/fixtures/domain-invalid.json")
+
+			if tt.setup != nil {
+				tt.setup(server)
+			}
+
+			res := cmdtest.Run(t, server, tt.args...)
+			if res.ExitCode != tt.exitCode {
+				t.Errorf("exit code = %d, want %d (%v)", res.ExitCode, tt.exitCode, res.Err)
+			}
+
+			cmdtest.AssertGolden(t, tt.name, res.Stderr)
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	server := cmdtest.NewServer(t)
+	server.Fallback = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	})
+
+	// Run twice, the timeout of a run must not leak into the next one.
+	for i := 0; i < 2; i++ {
+		start := time.Now()
+
+		res := cmdtest.Run(t, server, "domain", "list", "--timeout", "50ms")
+		if res.ExitCode != cmd.ExitTimeout {
+			t.Errorf("exit code %d, want %d (%v)", res.ExitCode, cmd.ExitTimeout, res.Err)
+		}
+
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("timed out after %v", elapsed)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/config"
+	"github.com/edsonmichaque/dnsimple-cli/internal/tui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func CmdUI(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:   "ui",
+		Short: "Browse accounts, domains and records in a terminal UI",
+		Long: heredoc.Doc(`
+			Browse accounts, their domains and the zone records, delegation signer
+			records and collaborators of each domain in a full-screen terminal UI.
+
+			Keys:
+			  enter, →        open the selected entry
+			  esc, ←          go back, or clear the filter
+			  /               filter the entries
+			  a               add a zone record
+			  e               edit the selected zone record
+			  d               delete the selected entry
+			  r               reload
+			  q, ctrl+c       quit
+
+			Changes are applied once confirmed. As with "domain delete", the zone
+			of a domain is backed up to --dir before the domain is deleted, and
+			registered domains that have not expired are refused unless
+			--force-registered is set.
+		`),
+		Args: cobra.NoArgs,
+		Example: heredoc.Doc(`
+			dnsimple ui
+			dnsimple ui --all-accounts
+		`),
+		Annotations: map[string]string{annotationMultiAccount: "true"},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if err := viper.BindPFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !isTerminal(opts.Stdin) || !isTerminal(opts.Stdout) {
+				return errors.New("ui needs an interactive terminal")
+			}
+
+			cfg, err := config.New()
+			if err != nil {
+				return err
+			}
+
+			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
+
+			accounts, _, err := lookupAccounts(cmd.Context(), apiClient)
+			if err != nil {
+				return err
+			}
+
+			if !cfg.AllAccounts {
+				accounts, err = selectAccounts(accounts, splitAccounts(cfg.Account))
+				if err != nil {
+					return err
+				}
+			}
+
+			removeDomain := func(ctx context.Context, accountID string, domain dnsimple.Domain) error {
+				if !viper.GetBool(flagForceRegistered) {
+					if err := checkRegistrations([]dnsimple.Domain{domain}, time.Now()); err != nil {
+						return err
+					}
+				}
+
+				_, err := deleteDomain(ctx, apiClient, accountID, domain.Name, viper.GetString(flagDir))
+
+				return err
+			}
+
+			return tui.New(apiClient, accounts, removeDomain).Run(cmd.Context())
+		},
+	}, opts)
+
+	cmd.Flags().String(flagDir, ".", "Directory where the zones are backed up before deleting a domain")
+	cmd.Flags().Bool(flagForceRegistered, false, "Delete registered domains that have not expired")
+
+	return cmd
+}
+
+// selectAccounts returns the accounts with the given IDs, in order.
+func selectAccounts(accounts []dnsimple.Account, ids []string) ([]dnsimple.Account, error) {
+	selected := make([]dnsimple.Account, 0, len(ids))
+
+	for _, id := range ids {
+		found := false
+
+		for _, account := range accounts {
+			if strconv.FormatInt(account.ID, 10) == id {
+				selected = append(selected, account)
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("no account with id %s", id)
+		}
+	}
+
+	return selected, nil
+}
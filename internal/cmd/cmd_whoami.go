@@ -17,7 +17,6 @@
 package cmd
 
 import (
-	"context"
 	"errors"
 	"io"
 
@@ -29,9 +28,10 @@ import (
 
 func CmdWhoami(opts *Options) *cobra.Command {
 	cmd := createCmd(&cobra.Command{
-		Use:   "whoami",
-		Short: "Check identity",
-		Args:  cobra.NoArgs,
+		Use:         "whoami",
+		Short:       "Check identity",
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{annotationCacheable: "true"},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			if err := viper.BindPFlags(cmd.Flags()); err != nil {
 				panic(err)
@@ -45,7 +45,7 @@ func CmdWhoami(opts *Options) *cobra.Command {
 
 			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
 
-			resp, err := apiClient.Identity.Whoami(context.Background())
+			resp, err := apiClient.Identity.Whoami(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -72,7 +72,7 @@ func CmdWhoami(opts *Options) *cobra.Command {
 		},
 	}, opts)
 
-	addOutputFlag(cmd, formatText)
+	addOutputFlag(cmd, formatText, formatJSON, formatYAML)
 	addQueryFlag(cmd)
 
 	return cmd
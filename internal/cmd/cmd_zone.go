@@ -0,0 +1,216 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/api"
+	"github.com/edsonmichaque/dnsimple-cli/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+const defaultConcurrency = 4
+
+func CmdZone(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "zone",
+		Short:   "Manage zones",
+		Aliases: []string{"zones"},
+	}
+
+	cmd.AddCommand(CmdZoneBackup(opts))
+	cmd.AddCommand(CmdZoneCopy(opts))
+	cmd.AddCommand(CmdZoneDiff(opts))
+	cmd.AddCommand(CmdZoneRecord(opts))
+	cmd.AddCommand(CmdZoneRestore(opts))
+
+	return cmd
+}
+
+// listAllZones fetches every zone of the account, following pagination.
+func listAllZones(ctx context.Context, client *api.Client, account string) ([]dnsimple.Zone, error) {
+	var zones []dnsimple.Zone
+
+	for page := 1; ; page++ {
+		resp, err := client.Zones.ListZones(ctx, account, &dnsimple.ZoneListOptions{
+			ListOptions: dnsimple.ListOptions{Page: dnsimple.Int(page)},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		zones = append(zones, resp.Data...)
+
+		if resp.Pagination == nil || page >= resp.Pagination.TotalPages {
+			return zones, nil
+		}
+	}
+}
+
+// listAllZoneRecords fetches every record of a zone, following pagination.
+func listAllZoneRecords(ctx context.Context, client *api.Client, account, zone string) ([]dnsimple.ZoneRecord, error) {
+	var records []dnsimple.ZoneRecord
+
+	for page := 1; ; page++ {
+		resp, err := client.Zones.ListRecords(ctx, account, zone, &dnsimple.ZoneRecordListOptions{
+			ListOptions: dnsimple.ListOptions{Page: dnsimple.Int(page)},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, resp.Data...)
+
+		if resp.Pagination == nil || page >= resp.Pagination.TotalPages {
+			return records, nil
+		}
+	}
+}
+
+func addConcurrencyFlag(cmd *cobra.Command) {
+	cmd.Flags().Int(flagConcurrency, defaultConcurrency, "Maximum number of concurrent requests")
+}
+
+// forEachZone calls fn for every zone, running at most concurrency calls at
+// once. It stops scheduling new calls after the first error and returns it.
+func forEachZone(zones []dnsimple.Zone, concurrency int, fn func(zone dnsimple.Zone) error) error {
+	return runConcurrentlyE(len(zones), concurrency, func(i int) error {
+		return fn(zones[i])
+	})
+}
+
+// runConcurrentlyE is like runConcurrently, but stops calling fn after the
+// first error and returns it.
+func runConcurrentlyE(n, concurrency int, fn func(i int) error) error {
+	var (
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	runConcurrently(n, concurrency, func(i int) {
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+
+		if failed {
+			return
+		}
+
+		if err := fn(i); err != nil {
+			mu.Lock()
+			if firstErr == nil {
+				firstErr = err
+			}
+			mu.Unlock()
+		}
+	})
+
+	return firstErr
+}
+
+// runConcurrently calls fn for every index in [0, n), running at most
+// concurrency calls at once, and waits for all of them to return.
+func runConcurrently(n, concurrency int, fn func(i int)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, concurrency)
+	)
+
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			fn(i)
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// applyZoneChanges applies changes to zone, running at most concurrency
+// requests at once. The returned slice holds the error of each change, in
+// the same order as changes.
+func applyZoneChanges(
+	ctx context.Context,
+	client *api.Client,
+	account, zone string,
+	changes []snapshot.Change,
+	concurrency int,
+) []error {
+	errs := make([]error, len(changes))
+
+	runConcurrently(len(changes), concurrency, func(i int) {
+		// Changes not started yet are skipped once the command is interrupted.
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+
+			return
+		}
+
+		var err error
+
+		switch c := changes[i]; c.Action {
+		case snapshot.ActionAdd:
+			_, err = client.Zones.CreateRecord(ctx, account, zone, recordAttributes(*c.New))
+		case snapshot.ActionChange:
+			_, err = client.Zones.UpdateRecord(ctx, account, zone, c.Old.ID, recordAttributes(*c.New))
+		case snapshot.ActionRemove:
+			_, err = client.Zones.DeleteRecord(ctx, account, zone, c.Old.ID)
+		default:
+			err = fmt.Errorf("unknown action %q", c.Action)
+		}
+
+		errs[i] = err
+	})
+
+	return errs
+}
+
+func recordAttributes(r dnsimple.ZoneRecord) dnsimple.ZoneRecordAttributes {
+	name := r.Name
+
+	return dnsimple.ZoneRecordAttributes{
+		Type:     r.Type,
+		Name:     &name,
+		Content:  r.Content,
+		TTL:      r.TTL,
+		Priority: r.Priority,
+		Regions:  r.Regions,
+	}
+}
+
+func isNotFound(err error) bool {
+	var errResp *dnsimple.ErrorResponse
+
+	return errors.As(err, &errResp) && errResp.HTTPResponse != nil && errResp.HTTPResponse.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,285 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/api"
+	"github.com/edsonmichaque/dnsimple-cli/internal/config"
+	"github.com/edsonmichaque/dnsimple-cli/internal/format"
+	"github.com/edsonmichaque/dnsimple-cli/internal/snapshot"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func CmdZoneBackup(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:   "backup",
+		Short: "Write a snapshot of zone records to disk",
+		Args:  cobra.NoArgs,
+		Example: heredoc.Doc(`
+			dnsimple zone backup --domain example.com
+			dnsimple zone backup --all --dir ./backups
+		`),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if err := viper.BindPFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.New()
+			if err != nil {
+				return err
+			}
+
+			var (
+				all    = viper.GetBool(flagAll)
+				domain = viper.GetString(configDomain)
+				dir    = viper.GetString(flagDir)
+			)
+
+			if !all && domain == "" {
+				return errors.New("either --domain or --all is required")
+			}
+
+			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
+
+			zones := []dnsimple.Zone{{Name: domain}}
+			if all {
+				zones, err = listAllZones(cmd.Context(), apiClient, cfg.Account)
+				if err != nil {
+					return err
+				}
+			}
+
+			var (
+				mu   sync.Mutex
+				done int
+			)
+
+			err = forEachZone(zones, viper.GetInt(flagConcurrency), func(zone dnsimple.Zone) error {
+				path, err := backupZone(cmd.Context(), apiClient, cfg.Account, zone.Name, dir)
+				if err != nil {
+					return fmt.Errorf("zone %s: %w", zone.Name, err)
+				}
+
+				mu.Lock()
+				done++
+				cmd.Printf("%s Backed up zone %s to %s\n", color.GreenString("✓"), zone.Name, path)
+				mu.Unlock()
+
+				return nil
+			})
+
+			if isInterrupted(err) {
+				return fmt.Errorf("interrupted after backing up %d of %d zone(s): %w", done, len(zones), cmd.Context().Err())
+			}
+
+			return err
+		},
+	}, opts)
+
+	cmd.Flags().String(configDomain, "", "Domain name")
+	cmd.Flags().Bool(flagAll, false, "Back up every zone in the account")
+	cmd.Flags().String(flagDir, ".", "Directory where snapshots are written")
+	cmd.MarkFlagsMutuallyExclusive(configDomain, flagAll)
+	addConcurrencyFlag(cmd)
+
+	return cmd
+}
+
+func CmdZoneRestore(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:   "restore",
+		Short: "Restore zone records from a snapshot",
+		Long: heredoc.Doc(`
+			Restore the records of a zone from a snapshot written by "zone backup".
+
+			The snapshot is compared with the live zone and the records that differ
+			are created, updated or deleted so that the zone matches the snapshot.
+			System records and domain metadata are never modified.
+		`),
+		Args: cobra.NoArgs,
+		Example: heredoc.Doc(`
+			dnsimple zone restore --from example.com-20230101T000000.000Z.json
+			dnsimple zone restore --domain example.com --from example.com-20230101T000000.000Z.json --confirm
+		`),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if err := viper.BindPFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.New()
+			if err != nil {
+				return err
+			}
+
+			snap, err := snapshot.Read(viper.GetString(flagFrom))
+			if err != nil {
+				return err
+			}
+
+			domain := viper.GetString(configDomain)
+			if domain == "" {
+				domain = snap.Zone
+			}
+
+			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
+
+			live, err := listAllZoneRecords(cmd.Context(), apiClient, cfg.Account, domain)
+			if err != nil {
+				return err
+			}
+
+			changes := snapshot.Compare(live, snap.Records)
+			if len(changes) == 0 {
+				cmd.Printf("%s Zone %s already matches the snapshot\n", color.GreenString("✓"), domain)
+
+				return nil
+			}
+
+			plan, err := format.Format(format.ZoneDiff{
+				From:    domain,
+				To:      viper.GetString(flagFrom),
+				Changes: changes,
+			}, &format.Options{Format: format.OutputFormatText})
+			if err != nil {
+				return err
+			}
+
+			if _, err := io.Copy(cmd.OutOrStdout(), plan); err != nil {
+				return err
+			}
+
+			if !viper.GetBool(configConfirm) {
+				confirmation, err := promptConfirmation(fmt.Sprintf("Apply %d change(s) to %s?", len(changes), domain), false)
+				if err != nil {
+					return err
+				}
+
+				if !confirmation {
+					return errors.New("no confirmation")
+				}
+			}
+
+			errs := applyZoneChanges(cmd.Context(), apiClient, cfg.Account, domain, changes, viper.GetInt(flagConcurrency))
+
+			return printZoneChangeResults(cmd, changes, errs)
+		},
+	}, opts)
+
+	cmd.Flags().String(configDomain, "", "Domain name, defaults to the zone of the snapshot")
+	cmd.Flags().String(flagFrom, "", "Snapshot file")
+	if err := cmd.MarkFlagRequired(flagFrom); err != nil {
+		panic(err)
+	}
+
+	addConfirmFlag(cmd)
+	addConcurrencyFlag(cmd)
+
+	return cmd
+}
+
+// backupZone writes a snapshot of zone into dir and returns its path.
+func backupZone(ctx context.Context, client *api.Client, account, zone, dir string) (string, error) {
+	records, err := listAllZoneRecords(ctx, client, account, zone)
+	if err != nil {
+		return "", err
+	}
+
+	var domain *dnsimple.Domain
+
+	resp, err := client.Domains.GetDomain(ctx, account, zone)
+	if err != nil && !isNotFound(err) {
+		return "", err
+	}
+
+	if err == nil {
+		domain = resp.Data
+	}
+
+	return snapshot.New(zone, domain, records).WriteDir(dir)
+}
+
+func printZoneChangeResults(cmd *cobra.Command, changes []snapshot.Change, errs []error) error {
+	var (
+		failed      int
+		interrupted error
+	)
+
+	for i, c := range changes {
+		r := c.Record()
+
+		if isInterrupted(errs[i]) {
+			if interrupted == nil {
+				interrupted = errs[i]
+			}
+
+			cmd.Printf("%s Did not %s %s %s %s\n", color.YellowString("-"), c.Action, recordLabel(r.Name), r.Type, r.Content)
+
+			continue
+		}
+
+		if errs[i] != nil {
+			failed++
+			cmd.Printf("%s Failed to %s %s %s %s: %v\n", color.RedString("✗"), c.Action, recordLabel(r.Name), r.Type, r.Content, errs[i])
+
+			continue
+		}
+
+		cmd.Printf("%s Applied %s %s %s %s\n", color.GreenString("✓"), c.Action, recordLabel(r.Name), r.Type, r.Content)
+	}
+
+	if interrupted != nil {
+		return fmt.Errorf("interrupted after applying %d of %d change(s): %w", countNil(errs), len(changes), interrupted)
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("%d of %d change(s) failed", failed, len(changes))
+	}
+
+	return nil
+}
+
+func countNil(errs []error) int {
+	n := 0
+
+	for _, err := range errs {
+		if err == nil {
+			n++
+		}
+	}
+
+	return n
+}
+
+func recordLabel(name string) string {
+	if name == "" {
+		return "@"
+	}
+
+	return name
+}
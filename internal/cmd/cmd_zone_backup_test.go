@@ -0,0 +1,118 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/cmdtest"
+	"github.com/edsonmichaque/dnsimple-cli/internal/fakeapi"
+)
+
+func TestZoneRestore(t *testing.T) {
+	api := fakeapi.New(dnsimple.Account{ID: 1010, Email: "ops@example.com"})
+
+	if _, err := api.AddDomain(1010, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, record := range []dnsimple.ZoneRecord{
+		{Name: "www", Type: "A", Content: "203.0.113.10"},
+		{Name: "", Type: "MX", Content: "mx.example.com", Priority: 10},
+	} {
+		if _, err := api.AddRecord(1010, "example.com", record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cname, err := api.AddRecord(1010, "example.com", dnsimple.ZoneRecord{Name: "api", Type: "CNAME", Content: "www.example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := cmdtest.NewServer(t)
+	server.Fallback = api
+
+	dir := t.TempDir()
+
+	res := cmdtest.Run(t, server, "zone", "backup", "--domain", "example.com", "--dir", dir)
+	if res.Err != nil {
+		t.Fatalf("backup: %v\n%s", res.Err, res.Stderr)
+	}
+
+	backups, err := filepath.Glob(filepath.Join(dir, "example.com-*.json"))
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("backups %v, %v", backups, err)
+	}
+
+	// Change a record, delete another and add one after the backup.
+	res = cmdtest.Run(t, server, "zone", "record", "replace", "--from", "203.0.113.10", "--to", "198.51.100.7", "--confirm")
+	if res.Err != nil {
+		t.Fatalf("replace: %v\n%s", res.Err, res.Stderr)
+	}
+
+	deleteRecord(t, server, "example.com", cname.ID)
+
+	if _, err := api.AddRecord(1010, "example.com", dnsimple.ZoneRecord{Name: "extra", Type: "TXT", Content: "added later"}); err != nil {
+		t.Fatal(err)
+	}
+
+	res = cmdtest.Run(t, server, "zone", "restore", "--from", backups[0], "--confirm", "--concurrency", "1")
+	if res.Err != nil {
+		t.Fatalf("restore: %v\n%s", res.Err, res.Stderr)
+	}
+
+	cmdtest.AssertGolden(t, "zone_restore", strings.ReplaceAll(res.Stdout, backups[0], "SNAPSHOT"))
+
+	res = cmdtest.Run(t, server, "zone", "record", "search", "--zone", "example.com", "--filter", "!system_record", "-o", "text")
+	if res.Err != nil {
+		t.Fatalf("search: %v\n%s", res.Err, res.Stderr)
+	}
+
+	cmdtest.AssertGolden(t, "zone_restore_records", res.Stdout)
+
+	res = cmdtest.Run(t, server, "zone", "restore", "--from", backups[0], "--confirm")
+	if res.Err != nil || !strings.Contains(res.Stdout, "already matches") {
+		t.Errorf("second restore: %v\n%s", res.Err, res.Stdout)
+	}
+}
+
+func deleteRecord(t *testing.T, server *cmdtest.Server, zone string, id int64) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/v2/1010/zones/%s/records/%d", server.URL, zone, id), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+fakeapi.Token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("delete record %d: %s", id, resp.Status)
+	}
+}
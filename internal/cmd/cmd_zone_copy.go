@@ -0,0 +1,217 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/config"
+	"github.com/edsonmichaque/dnsimple-cli/internal/format"
+	"github.com/edsonmichaque/dnsimple-cli/internal/snapshot"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func CmdZoneCopy(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:   "copy",
+		Short: "Copy the records of a zone to another zone",
+		Long: heredoc.Doc(`
+			Copy the records of a zone to another zone, possibly in another account or
+			using the credentials of another profile.
+
+			System, SOA and apex NS records are skipped, and names and hostnames that
+			reference the source apex are rewritten to reference the destination
+			apex. Records that already exist in the destination are left untouched.
+		`),
+		Args: cobra.NoArgs,
+		Example: heredoc.Doc(`
+			dnsimple zone copy --from example.com --to example.net --dry-run
+			dnsimple zone copy --from example.com --to example.net --to-account 1234
+			dnsimple zone copy --from example.com --to example.net --to-profile other --confirm
+		`),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if err := viper.BindPFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			srcCfg, err := config.New()
+			if err != nil {
+				return err
+			}
+
+			dstCfg, err := copyDestinationConfig(srcCfg)
+			if err != nil {
+				return err
+			}
+
+			var (
+				from = viper.GetString(flagFrom)
+				to   = viper.GetString(flagTo)
+			)
+
+			if strings.EqualFold(from, to) && srcCfg.Account == dstCfg.Account {
+				return errors.New("source and destination zones are the same")
+			}
+
+			var (
+				srcClient = opts.createClient(srcCfg.BaseURL, srcCfg.AccessToken)
+				dstClient = opts.createClient(dstCfg.BaseURL, dstCfg.AccessToken)
+			)
+
+			records, err := listAllZoneRecords(cmd.Context(), srcClient, srcCfg.Account, from)
+			if err != nil {
+				return err
+			}
+
+			live, err := listAllZoneRecords(cmd.Context(), dstClient, dstCfg.Account, to)
+			if err != nil {
+				return err
+			}
+
+			var (
+				changes []snapshot.Change
+				skipped int
+			)
+
+			for _, c := range snapshot.Compare(live, snapshot.Rebase(copyableRecords(records), from, to)) {
+				switch c.Action {
+				case snapshot.ActionAdd:
+					changes = append(changes, c)
+				case snapshot.ActionChange:
+					skipped++
+				}
+			}
+
+			if skipped != 0 {
+				cmd.PrintErrf("%d record(s) differ in %s and will be left unchanged, see \"zone diff\"\n", skipped, to)
+			}
+
+			if len(changes) == 0 {
+				cmd.Printf("%s Nothing to copy to %s\n", color.GreenString("✓"), to)
+
+				return nil
+			}
+
+			plan, err := format.Format(format.ZoneDiff{
+				From:    from,
+				To:      to,
+				Changes: changes,
+			}, &format.Options{Format: format.OutputFormatText})
+			if err != nil {
+				return err
+			}
+
+			if _, err := io.Copy(cmd.OutOrStdout(), plan); err != nil {
+				return err
+			}
+
+			if viper.GetBool(flagDryRun) {
+				return nil
+			}
+
+			if !viper.GetBool(configConfirm) {
+				confirmation, err := promptConfirmation(fmt.Sprintf("Create %d record(s) in %s?", len(changes), to), false)
+				if err != nil {
+					return err
+				}
+
+				if !confirmation {
+					return errors.New("no confirmation")
+				}
+			}
+
+			errs := applyZoneChanges(cmd.Context(), dstClient, dstCfg.Account, to, changes, viper.GetInt(flagConcurrency))
+
+			return printZoneChangeResults(cmd, changes, errs)
+		},
+	}, opts)
+
+	cmd.Flags().String(flagFrom, "", "Source zone")
+	cmd.Flags().String(flagTo, "", "Destination zone")
+	cmd.Flags().String(flagToAccount, "", "Destination account, defaults to the account of the destination profile")
+	cmd.Flags().String(flagToProfile, "", "Profile used for the destination, defaults to the active profile")
+	cmd.Flags().Bool(flagDryRun, false, "Print the records that would be copied without copying them")
+
+	for _, name := range []string{flagFrom, flagTo} {
+		if err := cmd.MarkFlagRequired(name); err != nil {
+			panic(err)
+		}
+	}
+
+	addConfirmFlag(cmd)
+	addConcurrencyFlag(cmd)
+
+	return cmd
+}
+
+// copyDestinationConfig returns the configuration used to write to the
+// destination zone, loading the destination profile when one is given.
+func copyDestinationConfig(src *config.Config) (*config.Config, error) {
+	dst := *src
+
+	if name := viper.GetString(flagToProfile); name != "" {
+		v, err := loadProfile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg, err := config.NewFromViper(v, false)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := cfg.ResolveAccessToken(); err != nil {
+			return nil, err
+		}
+
+		dst = *cfg
+	}
+
+	if account := viper.GetString(flagToAccount); account != "" {
+		dst.Account = account
+	}
+
+	if err := dst.Validate(); err != nil {
+		return nil, fmt.Errorf("destination: %w", err)
+	}
+
+	return &dst, nil
+}
+
+// copyableRecords drops the records that are managed by DNSimple or that
+// must not be duplicated in another zone.
+func copyableRecords(records []dnsimple.ZoneRecord) []dnsimple.ZoneRecord {
+	copyable := make([]dnsimple.ZoneRecord, 0, len(records))
+
+	for _, r := range records {
+		if r.SystemRecord || strings.EqualFold(r.Type, "SOA") || (strings.EqualFold(r.Type, "NS") && r.Name == "") {
+			continue
+		}
+
+		copyable = append(copyable, r)
+	}
+
+	return copyable
+}
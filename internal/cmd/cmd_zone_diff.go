@@ -0,0 +1,141 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"io"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/edsonmichaque/dnsimple-cli/internal/config"
+	"github.com/edsonmichaque/dnsimple-cli/internal/format"
+	"github.com/edsonmichaque/dnsimple-cli/internal/snapshot"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func CmdZoneDiff(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:   "diff [ZONE] [ZONE]",
+		Short: "Compare the records of two zones or of a zone and a snapshot",
+		Long: heredoc.Doc(`
+			Compare the records of two zones, or of a live zone and a snapshot written
+			by "zone backup".
+
+			Names and hostnames that reference the apex of the second zone are
+			rewritten relative to the apex of the first one, so that a zone and its
+			clone under another domain compare equal. System records are ignored.
+		`),
+		Args:              cobra.RangeArgs(0, 2),
+		ValidArgsFunction: completeZoneArgs(opts, 2),
+		Example: heredoc.Doc(`
+			dnsimple zone diff example.com example.net
+			dnsimple zone diff --domain example.com --against example.com-20230101T000000.000Z.json
+			dnsimple zone diff example.com example.net --output json
+		`),
+		Annotations: map[string]string{annotationCacheable: "true"},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if err := viper.BindPFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.New()
+			if err != nil {
+				return err
+			}
+
+			output := viper.GetString(flagOutput)
+			if output != formatText && output != formatJSON && output != formatYAML {
+				return errors.New("invalid output format")
+			}
+
+			var (
+				domain  = viper.GetString(configDomain)
+				against = viper.GetString(flagAgainst)
+			)
+
+			if len(args) == 2 && (domain != "" || against != "") {
+				return errors.New("--domain and --against cannot be used with zone arguments")
+			}
+
+			if len(args) != 2 && (len(args) != 0 || domain == "" || against == "") {
+				return errors.New("either two zones or --domain and --against are required")
+			}
+
+			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
+
+			var diff snapshot.Diff
+
+			if len(args) == 2 {
+				from, err := listAllZoneRecords(cmd.Context(), apiClient, cfg.Account, args[0])
+				if err != nil {
+					return err
+				}
+
+				to, err := listAllZoneRecords(cmd.Context(), apiClient, cfg.Account, args[1])
+				if err != nil {
+					return err
+				}
+
+				diff = snapshot.Diff{
+					From:    args[0],
+					To:      args[1],
+					Changes: snapshot.Compare(from, snapshot.Rebase(to, args[1], args[0])),
+				}
+			} else {
+				snap, err := snapshot.Read(against)
+				if err != nil {
+					return err
+				}
+
+				live, err := listAllZoneRecords(cmd.Context(), apiClient, cfg.Account, domain)
+				if err != nil {
+					return err
+				}
+
+				diff = snapshot.Diff{
+					From:    against,
+					To:      domain,
+					Changes: snapshot.Compare(snapshot.Rebase(snap.Records, snap.Zone, domain), live),
+				}
+			}
+
+			formattedOutput, err := format.Format(format.ZoneDiff(diff), &format.Options{
+				Format: format.OutputFormat(output),
+				// TODO: query should be only used for JSON and YAML output formats
+				Query: viper.GetString(flagQuery),
+			})
+			if err != nil {
+				return err
+			}
+
+			if _, err := io.Copy(cmd.OutOrStdout(), formattedOutput); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}, opts)
+
+	cmd.Flags().String(configDomain, "", "Live zone to compare")
+	cmd.Flags().String(flagAgainst, "", "Snapshot file to compare the live zone against")
+	addOutputFlag(cmd, formatText, formatJSON, formatYAML)
+	addQueryFlag(cmd)
+
+	return cmd
+}
@@ -0,0 +1,541 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/api"
+	"github.com/edsonmichaque/dnsimple-cli/internal/config"
+	"github.com/edsonmichaque/dnsimple-cli/internal/format"
+	"github.com/jmespath/go-jmespath"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func CmdZoneRecord(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "record",
+		Short:   "Manage zone records",
+		Aliases: []string{"records"},
+	}
+
+	cmd.AddCommand(CmdZoneRecordReplace(opts))
+	cmd.AddCommand(CmdZoneRecordSearch(opts))
+
+	return cmd
+}
+
+func CmdZoneRecordSearch(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:   "search",
+		Short: "Search records across all zones",
+		Args:  cobra.NoArgs,
+		Example: heredoc.Doc(`
+			dnsimple zone record search --content 203.0.113.10
+			dnsimple zone record search --type CNAME --content target.example.com
+			dnsimple zone record search --name '^_acme-challenge' --output json
+			dnsimple zone record search --filter 'ttl < ` + "`300`" + `'
+			dnsimple zone record search --content 203.0.113.10 --all-accounts
+		`),
+		Annotations: map[string]string{
+			annotationCacheable:    "true",
+			annotationMultiAccount: "true",
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if err := viper.BindPFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.New()
+			if err != nil {
+				return err
+			}
+
+			output := viper.GetString(flagOutput)
+			if output != formatTable && output != formatText && output != formatJSON && output != formatYAML {
+				return errors.New("invalid output format")
+			}
+
+			filter, err := newRecordFilter()
+			if err != nil {
+				return err
+			}
+
+			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
+
+			accounts, err := targetAccounts(cmd.Context(), apiClient, cfg)
+			if err != nil {
+				return err
+			}
+
+			var (
+				mu      sync.Mutex
+				matches = make([]format.ZoneRecordMatch, 0)
+			)
+
+			err = forEachAccount(accounts, func(account string) error {
+				accountMatches, err := searchRecords(cmd.Context(), apiClient, account, filter, viper.GetInt(flagConcurrency))
+				if err != nil {
+					return err
+				}
+
+				if len(accounts) > 1 {
+					for i := range accountMatches {
+						accountMatches[i].AccountID = account
+					}
+				}
+
+				mu.Lock()
+				matches = append(matches, accountMatches...)
+				mu.Unlock()
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			sort.SliceStable(matches, func(i, j int) bool {
+				return matches[i].AccountID < matches[j].AccountID
+			})
+
+			formattedOutput, err := format.Format(format.ZoneRecordMatchList(matches), &format.Options{
+				Format: format.OutputFormat(output),
+				// TODO: query should be only used for JSON and YAML output formats
+				Query: viper.GetString(flagQuery),
+			})
+			if err != nil {
+				return err
+			}
+
+			if _, err := io.Copy(cmd.OutOrStdout(), formattedOutput); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}, opts)
+
+	addRecordFilterFlags(cmd)
+	addConcurrencyFlag(cmd)
+	addOutputFlag(cmd, formatTable, formatText, formatJSON, formatYAML)
+	addQueryFlag(cmd)
+
+	return cmd
+}
+
+func CmdZoneRecordReplace(opts *Options) *cobra.Command {
+	cmd := createCmd(&cobra.Command{
+		Use:   "replace",
+		Short: "Replace record content across all zones",
+		Long: heredoc.Doc(`
+			Replace the content of every record matching --from with --to.
+
+			A plan of the records that will change is printed before anything is
+			updated. Records that fail to update are reported in the results and
+			keep their old content, so running the same command again retries
+			only the failures.
+		`),
+		Args: cobra.NoArgs,
+		Example: heredoc.Doc(`
+			dnsimple zone record replace --from 203.0.113.10 --to 198.51.100.7
+			dnsimple zone record replace --from 203.0.113.10 --to 198.51.100.7 --type A --zone '*.example.com'
+			dnsimple zone record replace --from 203.0.113.10 --to 198.51.100.7 --confirm --output json
+			dnsimple zone record replace --from 203.0.113.10 --to 198.51.100.7 --confirm --output text
+		`),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if err := viper.BindPFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.New()
+			if err != nil {
+				return err
+			}
+
+			output := viper.GetString(flagOutput)
+			if output != formatTable && output != formatText && output != formatJSON && output != formatYAML {
+				return errors.New("invalid output format")
+			}
+
+			var (
+				from = viper.GetString(flagFrom)
+				to   = viper.GetString(flagTo)
+			)
+
+			// An empty --from would match, and rewrite, every record.
+			if from == "" || to == "" {
+				return &usageError{err: errors.New("--from and --to must not be empty"), commandPath: cmd.CommandPath()}
+			}
+
+			if from == to {
+				return errors.New("--from and --to must be different")
+			}
+
+			filter := &recordFilter{
+				zone:       viper.GetString(flagZone),
+				content:    from,
+				recordType: strings.ToUpper(viper.GetString(flagType)),
+			}
+
+			if _, err := path.Match(filter.zone, ""); err != nil {
+				return fmt.Errorf("invalid zone pattern: %w", err)
+			}
+
+			apiClient := opts.createClient(cfg.BaseURL, cfg.AccessToken)
+			concurrency := viper.GetInt(flagConcurrency)
+
+			matches, err := searchRecords(cmd.Context(), apiClient, cfg.Account, filter, concurrency)
+			if err != nil {
+				return err
+			}
+
+			if len(matches) == 0 {
+				cmd.Printf("No records with content %s\n", from)
+
+				return nil
+			}
+
+			changes := make(format.ZoneRecordChangeList, 0, len(matches))
+			for _, m := range matches {
+				changes = append(changes, format.ZoneRecordChange{
+					Zone:     m.Zone,
+					RecordID: m.Record.ID,
+					Name:     m.Record.Name,
+					Type:     m.Record.Type,
+					From:     m.Record.Content,
+					To:       to,
+					Status:   "pending",
+				})
+			}
+
+			// The results list every change as well, so the plan is left out
+			// of JSON and YAML output unless someone has to confirm it.
+			if !viper.GetBool(configConfirm) || output == formatTable || output == formatText {
+				plan, err := format.Format(changes, &format.Options{Format: format.OutputFormatTable})
+				if err != nil {
+					return err
+				}
+
+				if _, err := io.Copy(cmd.OutOrStdout(), plan); err != nil {
+					return err
+				}
+			}
+
+			if !viper.GetBool(configConfirm) {
+				confirmation, err := promptConfirmation(fmt.Sprintf("Update %d record(s)?", len(changes)), false)
+				if err != nil {
+					return err
+				}
+
+				if !confirmation {
+					return errors.New("no confirmation")
+				}
+			}
+
+			failed := applyRecordChanges(cmd.Context(), apiClient, cfg.Account, changes, concurrency)
+
+			formattedOutput, err := format.Format(changes, &format.Options{
+				Format: format.OutputFormat(output),
+				// TODO: query should be only used for JSON and YAML output formats
+				Query: viper.GetString(flagQuery),
+			})
+			if err != nil {
+				return err
+			}
+
+			if _, err := io.Copy(cmd.OutOrStdout(), formattedOutput); err != nil {
+				return err
+			}
+
+			if err := cmd.Context().Err(); err != nil {
+				return fmt.Errorf("interrupted after updating %d of %d record(s); rerun the same command to finish: %w", countStatus(changes, "updated"), len(changes), err)
+			}
+
+			if failed != 0 {
+				return fmt.Errorf("%d of %d record(s) failed to update; rerun the same command to retry them", failed, len(changes))
+			}
+
+			return nil
+		},
+	}, opts)
+
+	cmd.Flags().String(flagFrom, "", "Current record content")
+	cmd.Flags().String(flagTo, "", "New record content")
+	cmd.Flags().String(flagType, "", "Only replace records of this type")
+	cmd.Flags().String(flagZone, "", "Only replace records in zones whose name matches this glob pattern")
+
+	for _, name := range []string{flagFrom, flagTo} {
+		if err := cmd.MarkFlagRequired(name); err != nil {
+			panic(err)
+		}
+	}
+
+	addConfirmFlag(cmd)
+	addConcurrencyFlag(cmd)
+	addOutputFlag(cmd, formatTable, formatText, formatJSON, formatYAML)
+	addQueryFlag(cmd)
+
+	return cmd
+}
+
+// applyRecordChanges updates the content of every record in changes,
+// recording the outcome of each update in place. It returns the number of
+// updates that failed.
+func applyRecordChanges(
+	ctx context.Context,
+	client *api.Client,
+	account string,
+	changes format.ZoneRecordChangeList,
+	concurrency int,
+) int {
+	var (
+		mu     sync.Mutex
+		failed int
+	)
+
+	runConcurrently(len(changes), concurrency, func(i int) {
+		change := &changes[i]
+
+		// Updates not started yet are skipped once the command is interrupted.
+		if ctx.Err() != nil {
+			change.Status = "skipped"
+
+			return
+		}
+
+		_, err := client.Zones.UpdateRecord(ctx, account, change.Zone, change.RecordID, dnsimple.ZoneRecordAttributes{
+			Content: change.To,
+		})
+		if err != nil {
+			change.Status = "failed"
+			change.Error = err.Error()
+
+			mu.Lock()
+			failed++
+			mu.Unlock()
+
+			return
+		}
+
+		change.Status = "updated"
+	})
+
+	return failed
+}
+
+func countStatus(changes format.ZoneRecordChangeList, status string) int {
+	n := 0
+
+	for _, change := range changes {
+		if change.Status == status {
+			n++
+		}
+	}
+
+	return n
+}
+
+// recordFilter selects zone records. Empty criteria match every record.
+type recordFilter struct {
+	zone       string
+	content    string
+	recordType string
+	name       *regexp.Regexp
+	predicate  *jmespath.JMESPath
+}
+
+func newRecordFilter() (*recordFilter, error) {
+	filter := &recordFilter{
+		zone:       viper.GetString(flagZone),
+		content:    viper.GetString(flagContent),
+		recordType: strings.ToUpper(viper.GetString(flagType)),
+	}
+
+	if _, err := path.Match(filter.zone, ""); err != nil {
+		return nil, fmt.Errorf("invalid zone pattern: %w", err)
+	}
+
+	if name := viper.GetString(flagName); name != "" {
+		re, err := regexp.Compile(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid name pattern: %w", err)
+		}
+
+		filter.name = re
+	}
+
+	if expr := viper.GetString(flagFilter); expr != "" {
+		predicate, err := jmespath.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter expression: %w", err)
+		}
+
+		filter.predicate = predicate
+	}
+
+	return filter, nil
+}
+
+func (f *recordFilter) matchZone(zone string) bool {
+	if f.zone == "" {
+		return true
+	}
+
+	ok, _ := path.Match(f.zone, zone)
+
+	return ok
+}
+
+func (f *recordFilter) match(record dnsimple.ZoneRecord) (bool, error) {
+	if f.content != "" && record.Content != f.content {
+		return false, nil
+	}
+
+	if f.recordType != "" && !strings.EqualFold(record.Type, f.recordType) {
+		return false, nil
+	}
+
+	if f.name != nil && !f.name.MatchString(record.Name) {
+		return false, nil
+	}
+
+	if f.predicate != nil {
+		raw, err := json.Marshal(record)
+		if err != nil {
+			return false, err
+		}
+
+		var data interface{}
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return false, err
+		}
+
+		result, err := f.predicate.Search(data)
+		if err != nil {
+			return false, err
+		}
+
+		return isTruthy(result), nil
+	}
+
+	return true, nil
+}
+
+// isTruthy applies the JMESPath notion of truth: false, null and empty
+// strings, arrays and objects are false; everything else is true.
+func isTruthy(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case string:
+		return t != ""
+	case []interface{}:
+		return len(t) != 0
+	case map[string]interface{}:
+		return len(t) != 0
+	default:
+		return true
+	}
+}
+
+// searchRecords walks every zone of the account and returns the records
+// accepted by filter, sorted by zone and record ID.
+func searchRecords(
+	ctx context.Context,
+	client *api.Client,
+	account string,
+	filter *recordFilter,
+	concurrency int,
+) ([]format.ZoneRecordMatch, error) {
+	allZones, err := listAllZones(ctx, client, account)
+	if err != nil {
+		return nil, err
+	}
+
+	zones := make([]dnsimple.Zone, 0, len(allZones))
+
+	for _, zone := range allZones {
+		if filter.matchZone(zone.Name) {
+			zones = append(zones, zone)
+		}
+	}
+
+	var (
+		mu      sync.Mutex
+		matches = make([]format.ZoneRecordMatch, 0)
+	)
+
+	err = forEachZone(zones, concurrency, func(zone dnsimple.Zone) error {
+		records, err := listAllZoneRecords(ctx, client, account, zone.Name)
+		if err != nil {
+			return fmt.Errorf("zone %s: %w", zone.Name, err)
+		}
+
+		for _, record := range records {
+			ok, err := filter.match(record)
+			if err != nil {
+				return err
+			}
+
+			if ok {
+				mu.Lock()
+				matches = append(matches, format.ZoneRecordMatch{Zone: zone.Name, Record: record})
+				mu.Unlock()
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Zone != matches[j].Zone {
+			return matches[i].Zone < matches[j].Zone
+		}
+
+		return matches[i].Record.ID < matches[j].Record.ID
+	})
+
+	return matches, nil
+}
+
+func addRecordFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flagZone, "", "Only search zones whose name matches this glob pattern")
+	cmd.Flags().String(flagContent, "", "Match records with this exact content")
+	cmd.Flags().String(flagType, "", "Match records of this type")
+	cmd.Flags().String(flagName, "", "Match records whose name matches this regular expression")
+	cmd.Flags().String(flagFilter, "", "Match records for which this JMESPath expression is true")
+}
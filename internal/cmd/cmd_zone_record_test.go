@@ -0,0 +1,95 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/edsonmichaque/dnsimple-cli/internal/cmdtest"
+)
+
+func TestZoneRecordReplace(t *testing.T) {
+	server := newServer(t)
+	server.HandleFile(http.MethodPatch, "/v2/1010/zones/example.com/records/11", http.StatusOK, "testdata/fixtures/record-updated.json")
+	server.Handle(http.MethodPatch, "/v2/1010/zones/example.net/records/22", http.StatusInternalServerError, `{"message":"Internal server error"}`)
+
+	res := cmdtest.Run(t, server,
+		"zone", "record", "replace",
+		"--from", "203.0.113.10",
+		"--to", "198.51.100.7",
+		"--confirm",
+		"--concurrency", "1",
+	)
+
+	if res.Err == nil {
+		t.Fatal("expected the failed update to be reported")
+	}
+
+	cmdtest.AssertGolden(t, "zone_record_replace", res.Stdout)
+	cmdtest.AssertGolden(t, "zone_record_replace_stderr", res.Stderr)
+
+	var patched []string
+
+	for _, req := range server.Requests() {
+		if req.Method != http.MethodPatch {
+			continue
+		}
+
+		patched = append(patched, req.Path)
+
+		if want := `{"content":"198.51.100.7"}`; req.Body != want+"\n" && req.Body != want {
+			t.Errorf("%s body = %s, want %s", req.Path, req.Body, want)
+		}
+	}
+
+	if len(patched) != 2 {
+		t.Errorf("patched %v, want both records", patched)
+	}
+}
+
+func TestZoneRecordReplaceOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+	}{
+		{name: "zone_record_replace_text", output: "text"},
+		{name: "zone_record_replace_json", output: "json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newServer(t)
+			server.HandleFile(http.MethodPatch, "/v2/1010/zones/example.com/records/11", http.StatusOK, "testdata/fixtures/record-updated.json")
+			server.HandleFile(http.MethodPatch, "/v2/1010/zones/example.net/records/22", http.StatusOK, "testdata/fixtures/record-updated.json")
+
+			res := cmdtest.Run(t, server,
+				"zone", "record", "replace",
+				"--from", "203.0.113.10",
+				"--to", "198.51.100.7",
+				"--confirm",
+				"--concurrency", "1",
+				"--output", tt.output,
+			)
+			if res.Err != nil {
+				t.Fatalf("unexpected error: %v\n%s", res.Err, res.Stderr)
+			}
+
+			cmdtest.AssertGolden(t, tt.name, res.Stdout)
+		})
+	}
+}
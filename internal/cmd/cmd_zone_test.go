@@ -0,0 +1,119 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/api"
+	"github.com/edsonmichaque/dnsimple-cli/internal/snapshot"
+)
+
+// fakeZones records the changes made to zone records and fails the ones
+// listed in fail.
+type fakeZones struct {
+	api.Zones
+
+	mu      sync.Mutex
+	fail    map[int64]bool
+	created []string
+	updated []int64
+	deleted []int64
+}
+
+func (z *fakeZones) CreateRecord(_ context.Context, _, _ string, attrs dnsimple.ZoneRecordAttributes) (*dnsimple.ZoneRecordResponse, error) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	z.created = append(z.created, attrs.Content)
+
+	return &dnsimple.ZoneRecordResponse{}, nil
+}
+
+func (z *fakeZones) UpdateRecord(_ context.Context, _, _ string, id int64, _ dnsimple.ZoneRecordAttributes) (*dnsimple.ZoneRecordResponse, error) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	if z.fail[id] {
+		return nil, errors.New("update failed")
+	}
+
+	z.updated = append(z.updated, id)
+
+	return &dnsimple.ZoneRecordResponse{}, nil
+}
+
+func (z *fakeZones) DeleteRecord(_ context.Context, _, _ string, id int64) (*dnsimple.ZoneRecordResponse, error) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	z.deleted = append(z.deleted, id)
+
+	return &dnsimple.ZoneRecordResponse{}, nil
+}
+
+func TestApplyZoneChanges(t *testing.T) {
+	changes := []snapshot.Change{
+		{Action: snapshot.ActionAdd, New: &dnsimple.ZoneRecord{Type: "A", Content: "198.51.100.7"}},
+		{Action: snapshot.ActionChange, Old: &dnsimple.ZoneRecord{ID: 1}, New: &dnsimple.ZoneRecord{Type: "A", Content: "198.51.100.8"}},
+		{Action: snapshot.ActionChange, Old: &dnsimple.ZoneRecord{ID: 2}, New: &dnsimple.ZoneRecord{Type: "A", Content: "198.51.100.9"}},
+		{Action: snapshot.ActionRemove, Old: &dnsimple.ZoneRecord{ID: 3}},
+	}
+
+	zones := &fakeZones{fail: map[int64]bool{2: true}}
+
+	errs := applyZoneChanges(context.Background(), &api.Client{Zones: zones}, "1010", "example.com", changes, 2)
+
+	for i, wantErr := range []bool{false, false, true, false} {
+		if (errs[i] != nil) != wantErr {
+			t.Errorf("change %d: error = %v, want error %t", i, errs[i], wantErr)
+		}
+	}
+
+	if len(zones.created) != 1 || len(zones.updated) != 1 || len(zones.deleted) != 1 {
+		t.Errorf("created %v, updated %v, deleted %v", zones.created, zones.updated, zones.deleted)
+	}
+}
+
+func TestApplyZoneChangesInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	changes := []snapshot.Change{
+		{Action: snapshot.ActionRemove, Old: &dnsimple.ZoneRecord{ID: 1}},
+	}
+
+	zones := &fakeZones{}
+
+	errs := applyZoneChanges(ctx, &api.Client{Zones: zones}, "1010", "example.com", changes, 1)
+
+	if !isInterrupted(errs[0]) {
+		t.Errorf("error = %v, want the change to be skipped", errs[0])
+	}
+
+	if len(zones.deleted) != 0 {
+		t.Errorf("deleted %v after the context was cancelled", zones.deleted)
+	}
+
+	if code := ExitCode(errs[0]); code != ExitInterrupted {
+		t.Errorf("exit code = %d, want %d", code, ExitInterrupted)
+	}
+}
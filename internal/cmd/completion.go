@@ -0,0 +1,294 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/edsonmichaque/dnsimple-cli/internal/api"
+	"github.com/edsonmichaque/dnsimple-cli/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	// completionCacheTTL is how long completions reuse API responses, so
+	// that pressing TAB again does not wait for the API.
+	completionCacheTTL = time.Minute
+	completionTimeout  = 5 * time.Second
+)
+
+// completing tells whether the running command computes shell completions.
+var completing bool
+
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// registerCompletions completes the values of the flags of cmd and of its
+// subcommands that name DNSimple resources, profiles and output formats.
+func registerCompletions(cmd *cobra.Command, opts *Options) {
+	completions := map[string]completionFunc{
+		configCollaboratorID: completeCollaborators(opts),
+		configDomain:         completeDomains(opts),
+		flagOutput:           completeOutputFormats,
+		flagProfile:          completeProfiles,
+		flagRecordID:         completeDelegationSignerRecords(opts),
+	}
+
+	cmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+		fn, ok := completions[f.Name]
+		if !ok || f.Value.Type() == "stringSlice" {
+			return
+		}
+
+		if err := cmd.RegisterFlagCompletionFunc(f.Name, fn); err != nil {
+			panic(err)
+		}
+	})
+
+	for _, c := range cmd.Commands() {
+		registerCompletions(c, opts)
+	}
+}
+
+// completionClient returns a client for the configuration of the command
+// line being completed, with a context bounded by completionTimeout.
+func completionClient(cmd *cobra.Command, opts *Options) (context.Context, context.CancelFunc, *api.Client, *config.Config, error) {
+	if err := viper.BindPFlags(cmd.Flags()); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	// The flags being completed are parsed after the configuration is read,
+	// so read it again to honour --profile and --config-file.
+	initConfig()
+
+	completing = true
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), completionTimeout)
+
+	config.AccountResolver = accountResolver(ctx, opts)
+
+	// A prompt would be written into the completions read by the shell, so a
+	// secret file that needs a passphrase completes nothing.
+	config.PassphraseFunc = completionPassphrase
+
+	cfg, err := config.New()
+	if err != nil {
+		cancel()
+
+		return nil, nil, nil, nil, err
+	}
+
+	return ctx, cancel, opts.createClient(cfg.BaseURL, cfg.AccessToken), cfg, nil
+}
+
+// completionPassphrase reads the passphrase of the secret file from the
+// environment, without prompting for it.
+func completionPassphrase() (string, error) {
+	if passphrase := os.Getenv(envDNSimplePassphrase); passphrase != "" {
+		return passphrase, nil
+	}
+
+	return "", fmt.Errorf("the secret file needs a passphrase; set %s to complete", envDNSimplePassphrase)
+}
+
+// completionError logs err for debugging and completes nothing, so that the
+// shell does not fall back to file names.
+func completionError(err error) ([]string, cobra.ShellCompDirective) {
+	cobra.CompErrorln(err.Error())
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeDomains(opts *Options) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		ctx, cancel, client, cfg, err := completionClient(cmd, opts)
+		if err != nil {
+			return completionError(err)
+		}
+		defer cancel()
+
+		accounts, err := targetAccounts(ctx, client, cfg)
+		if err != nil {
+			return completionError(err)
+		}
+
+		var completions []string
+
+		for _, account := range accounts {
+			domains, err := listAllDomains(ctx, client, account)
+			if err != nil {
+				return completionError(err)
+			}
+
+			for _, domain := range domains {
+				if strings.HasPrefix(domain.Name, toComplete) {
+					completions = append(completions, domain.Name+"\t"+domain.State)
+				}
+			}
+		}
+
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeZoneArgs completes the zones given as arguments, up to max of them.
+func completeZoneArgs(opts *Options, max int) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= max {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		ctx, cancel, client, cfg, err := completionClient(cmd, opts)
+		if err != nil {
+			return completionError(err)
+		}
+		defer cancel()
+
+		zones, err := listAllZones(ctx, client, cfg.Account)
+		if err != nil {
+			return completionError(err)
+		}
+
+		var completions []string
+
+		for _, zone := range zones {
+			if strings.HasPrefix(zone.Name, toComplete) {
+				completions = append(completions, zone.Name)
+			}
+		}
+
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func completeDelegationSignerRecords(opts *Options) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		ctx, cancel, client, cfg, err := completionClient(cmd, opts)
+		if err != nil {
+			return completionError(err)
+		}
+		defer cancel()
+
+		domain := viper.GetString(configDomain)
+		if domain == "" {
+			return cobra.AppendActiveHelp(nil, "Set --domain to complete its records"), cobra.ShellCompDirectiveNoFileComp
+		}
+
+		records, err := listAllDelegationSignerRecords(ctx, client, cfg.Account, domain)
+		if err != nil {
+			return completionError(err)
+		}
+
+		var completions []string
+
+		for _, record := range records {
+			id := strconv.FormatInt(record.ID, 10)
+			if strings.HasPrefix(id, toComplete) {
+				completions = append(completions, id+"\tkeytag "+record.Keytag+", algorithm "+record.Algorithm)
+			}
+		}
+
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func completeCollaborators(opts *Options) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		ctx, cancel, client, cfg, err := completionClient(cmd, opts)
+		if err != nil {
+			return completionError(err)
+		}
+		defer cancel()
+
+		domain := viper.GetString(configDomain)
+		if domain == "" {
+			return cobra.AppendActiveHelp(nil, "Set --domain to complete its collaborators"), cobra.ShellCompDirectiveNoFileComp
+		}
+
+		collaborators, err := listAllCollaborators(ctx, client, cfg.Account, domain)
+		if err != nil {
+			return completionError(err)
+		}
+
+		var completions []string
+
+		for _, collaborator := range collaborators {
+			id := strconv.FormatInt(collaborator.ID, 10)
+			if strings.HasPrefix(id, toComplete) {
+				completions = append(completions, id+"\t"+collaborator.UserEmail)
+			}
+		}
+
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	profiles, err := listProfiles()
+	if err != nil {
+		return completionError(err)
+	}
+
+	var completions []string
+
+	for _, p := range profiles {
+		if strings.HasPrefix(p.Name, toComplete) {
+			completions = append(completions, p.Name+"\t"+p.Path)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProfileArgs completes the profiles given as arguments, up to max
+// of them.
+func completeProfileArgs(max int) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= max {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return completeProfiles(cmd, args, toComplete)
+	}
+}
+
+var outputFormatDescriptions = map[string]string{
+	formatTable: "Table",
+	formatText:  "Plain text",
+	formatJSON:  "JSON",
+	formatYAML:  "YAML",
+}
+
+// completeOutputFormats completes the formats the command registered with
+// addOutputFlag.
+func completeOutputFormats(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var completions []string
+
+	if f := cmd.Flags().Lookup(flagOutput); f != nil {
+		for _, format := range f.Annotations[annotationOutputFormats] {
+			completions = append(completions, format+"\t"+outputFormatDescriptions[format])
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,86 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/edsonmichaque/dnsimple-cli/internal/cmdtest"
+	"github.com/edsonmichaque/dnsimple-cli/internal/config"
+	"github.com/spf13/viper"
+)
+
+func TestCompleteWithPassphrase(t *testing.T) {
+	const passphrase = "correct horse"
+
+	server := newServer(t)
+
+	cmdtest.Run(t, server, "config", "profile", "list")
+
+	saved := config.PassphraseFunc
+	t.Cleanup(func() { config.PassphraseFunc = saved })
+
+	config.PassphraseFunc = func() (string, error) { return passphrase, nil }
+
+	dir := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "dnsimple")
+	backend := config.EncryptedFileBackend{Path: filepath.Join(dir, "locked.secret")}
+
+	if err := backend.Store(profileToken); err != nil {
+		t.Fatal(err)
+	}
+
+	v := viper.New()
+	v.Set("account", "1010")
+	v.Set("secret-file", backend.Path)
+
+	if err := v.WriteConfigAs(filepath.Join(dir, "locked.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	args := []string{"__complete", "domain", "delete", "--profile", "locked", "--access-token", "", "--domain", "example.n"}
+
+	// Without the passphrase in the environment, nothing is completed rather
+	// than prompting for it.
+	t.Setenv("DNSIMPLE_PASSPHRASE", "")
+
+	res := cmdtest.Run(t, server, args...)
+	if res.Err != nil {
+		t.Fatalf("%v\n%s", res.Err, res.Stderr)
+	}
+
+	if res.Stdout != ":4\n" {
+		t.Errorf("completions = %q, want none", res.Stdout)
+	}
+
+	if reqs := server.Requests(); len(reqs) != 0 {
+		t.Errorf("made %d request(s) without an access token", len(reqs))
+	}
+
+	t.Setenv("DNSIMPLE_PASSPHRASE", passphrase)
+
+	res = cmdtest.Run(t, server, args...)
+	if res.Err != nil {
+		t.Fatalf("%v\n%s", res.Err, res.Stderr)
+	}
+
+	if !strings.Contains(res.Stdout, "example.net") {
+		t.Errorf("completions = %q, want example.net", res.Stdout)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/spf13/cobra"
+)
+
+// usageError is an error in the command line, such as an unknown flag.
+type usageError struct {
+	err         error
+	commandPath string
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+// newUsageError returns err, an error in the command line of cmd, as a
+// usageError.
+func newUsageError(cmd *cobra.Command, err error) error {
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		return err
+	}
+
+	return &usageError{err: err, commandPath: cmd.CommandPath()}
+}
+
+// errorOutput is the error printed to stderr when the output format is JSON.
+type errorOutput struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Message  string              `json:"message"`
+	ExitCode int                 `json:"exit_code"`
+	Status   int                 `json:"status,omitempty"`
+	Method   string              `json:"method,omitempty"`
+	URL      string              `json:"url,omitempty"`
+	Fields   map[string][]string `json:"fields,omitempty"`
+}
+
+func newErrorDetail(err error) errorDetail {
+	detail := errorDetail{
+		Message:  err.Error(),
+		ExitCode: ExitCode(err),
+	}
+
+	var apiErr *dnsimple.ErrorResponse
+	if errors.As(err, &apiErr) && apiErr.HTTPResponse != nil {
+		detail.Status = apiErr.HTTPResponse.StatusCode
+		detail.Fields = apiErr.AttributeErrors
+
+		if req := apiErr.HTTPResponse.Request; req != nil {
+			detail.Method = req.Method
+			detail.URL = req.URL.String()
+		}
+	}
+
+	return detail
+}
+
+// printError writes err to w, as a JSON object when output is JSON and
+// otherwise as text followed by the validation errors of each field.
+func printError(w io.Writer, err error, output string) {
+	detail := newErrorDetail(err)
+
+	if output == formatJSON {
+		data, jsonErr := json.MarshalIndent(errorOutput{Error: detail}, "", "  ")
+		if jsonErr == nil {
+			fmt.Fprintln(w, string(data))
+
+			return
+		}
+	}
+
+	fmt.Fprintf(w, "Error: %s\n", detail.Message)
+
+	fields := make([]string, 0, len(detail.Fields))
+	for field := range detail.Fields {
+		fields = append(fields, field)
+	}
+
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		for _, msg := range detail.Fields[field] {
+			fmt.Fprintf(w, "  - %s: %s\n", field, msg)
+		}
+	}
+
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		fmt.Fprintf(w, "Run '%s --help' for usage.\n", usageErr.commandPath)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+)
+
+// Exit codes of the process, listed in the help of the root command. Commands
+// interrupted by a signal or by --timeout report what they completed before
+// exiting.
+const (
+	ExitOK          = 0
+	ExitError       = 1
+	ExitUsage       = 2
+	ExitAuth        = 3
+	ExitNotFound    = 4
+	ExitValidation  = 5
+	ExitRateLimited = 6
+	ExitServer      = 7
+	ExitNetwork     = 8
+	ExitTimeout     = 124
+	ExitInterrupted = 130
+)
+
+// ExitCode returns the exit code of the process for the error returned by Run.
+func ExitCode(err error) int {
+	var (
+		usageErr *usageError
+		apiErr   *dnsimple.ErrorResponse
+		authErr  *dnsimple.ExchangeAuthorizationError
+		urlErr   *url.Error
+		netErr   net.Error
+	)
+
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, context.DeadlineExceeded):
+		return ExitTimeout
+	case errors.Is(err, context.Canceled):
+		return ExitInterrupted
+	case errors.As(err, &usageErr):
+		return ExitUsage
+	case errors.As(err, &apiErr) && apiErr.HTTPResponse != nil:
+		return statusExitCode(apiErr.HTTPResponse.StatusCode)
+	case errors.As(err, &authErr):
+		return ExitAuth
+	case errors.As(err, &urlErr), errors.As(err, &netErr):
+		return ExitNetwork
+	default:
+		return ExitError
+	}
+}
+
+func statusExitCode(status int) int {
+	switch {
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return ExitAuth
+	case status == http.StatusNotFound:
+		return ExitNotFound
+	case status == http.StatusBadRequest, status == http.StatusConflict, status == http.StatusUnprocessableEntity:
+		return ExitValidation
+	case status == http.StatusTooManyRequests:
+		return ExitRateLimited
+	case status >= http.StatusInternalServerError:
+		return ExitServer
+	default:
+		return ExitError
+	}
+}
+
+// isInterrupted reports whether err is the result of the command being
+// cancelled or timing out.
+func isInterrupted(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
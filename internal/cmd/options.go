@@ -20,9 +20,17 @@ import (
 	"context"
 	"errors"
 	"io"
+	"net/http"
 	"os"
+	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/api"
+	"github.com/edsonmichaque/dnsimple-cli/internal/transport"
+	"github.com/spf13/viper"
+	"golang.org/x/oauth2"
 )
 
 func NewOptions() (*Options, error) {
@@ -31,13 +39,18 @@ func NewOptions() (*Options, error) {
 		return nil, err
 	}
 
-	return &Options{
-		Stdin:         os.Stdin,
-		Stderr:        os.Stderr,
-		Stdout:        os.Stdout,
-		WorkDir:       wd,
-		ClientBuilder: buildClient,
-	}, nil
+	opts := &Options{
+		Stdin:   os.Stdin,
+		Stderr:  os.Stderr,
+		Stdout:  os.Stdout,
+		WorkDir: wd,
+	}
+
+	opts.ClientBuilder = func(url, token string) *api.Client {
+		return buildClient(url, token, opts.Stderr)
+	}
+
+	return opts, nil
 }
 
 type Options struct {
@@ -45,7 +58,7 @@ type Options struct {
 	Stdin         io.Reader
 	Stderr        io.Writer
 	WorkDir       string
-	ClientBuilder func(string, string) *dnsimple.Client
+	ClientBuilder func(string, string) *api.Client
 }
 
 func (c Options) Validate() error {
@@ -56,22 +69,113 @@ func (c Options) Validate() error {
 	return nil
 }
 
-func (c Options) createClient(url, token string) *dnsimple.Client {
-	clientBuilder := c.ClientBuilder
-
-	if clientBuilder == nil {
-		clientBuilder = buildClient
+func (c Options) createClient(url, token string) *api.Client {
+	if c.ClientBuilder == nil {
+		return buildClient(url, token, c.Stderr)
 	}
 
-	return clientBuilder(url, token)
+	return c.ClientBuilder(url, token)
 }
 
-func buildClient(url, token string) *dnsimple.Client {
-	client := dnsimple.NewClient(dnsimple.StaticTokenHTTPClient(context.Background(), token))
+func buildClient(url, token string, stderr io.Writer) *api.Client {
+	// The OAuth2 client sends its requests through the client in the context.
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{
+		Transport: httpTransport(stderr),
+	})
+
+	client := dnsimple.NewClient(dnsimple.StaticTokenHTTPClient(ctx, token))
 
 	if url != "" {
 		client.BaseURL = url
 	}
 
-	return client
+	return api.New(client)
+}
+
+// httpTransport returns the transport of the API requests, which retries
+// failed requests and traces them when asked to.
+func httpTransport(stderr io.Writer) http.RoundTripper {
+	rt := cassetteTransport()
+
+	// Tracing sits below the retries so that every attempt is logged.
+	if viper.GetBool(flagDebugHTTP) || viper.GetBool(flagDebugHTTPBody) {
+		if stderr == nil {
+			stderr = os.Stderr
+		}
+
+		rt = transport.NewDebug(rt, stderr, viper.GetBool(flagDebugHTTPBody))
+	}
+
+	rt = transport.NewRetry(rt, viper.GetInt(flagMaxRetries))
+
+	dir, err := cacheDir()
+	if err != nil {
+		return rt
+	}
+
+	return transport.NewCache(rt, filepath.Join(dir, activeProfile), cacheTTL())
+}
+
+// cacheReads tells whether the running command may answer its requests from
+// the response cache, see annotationCacheable.
+var cacheReads bool
+
+// cacheTTL returns how long the running command may use cached responses.
+// Responses are neither cached nor read when recording or replaying a
+// cassette, so that the cassette holds every interaction. Completions always
+// use the cache, for a short time unless the configured one is longer.
+func cacheTTL() time.Duration {
+	if !(cacheReads || completing) || viper.GetBool(flagNoCache) {
+		return 0
+	}
+
+	if viper.GetString(flagRecordCassette) != "" || viper.GetString(flagReplayCassette) != "" {
+		return 0
+	}
+
+	ttl := viper.GetDuration(configCacheTTL)
+	if completing && ttl < completionCacheTTL {
+		return completionCacheTTL
+	}
+
+	return ttl
+}
+
+var (
+	cassetteMu sync.Mutex
+	cassette   http.RoundTripper
+)
+
+// cassetteTransport returns the transport sending the API requests: one
+// recording them to --record-cassette or replaying them from
+// --replay-cassette, shared by all the clients of the command, or else the
+// default transport.
+func cassetteTransport() http.RoundTripper {
+	cassetteMu.Lock()
+	defer cassetteMu.Unlock()
+
+	if cassette != nil {
+		return cassette
+	}
+
+	if path := viper.GetString(flagReplayCassette); path != "" {
+		cassette = transport.NewReplayer(path)
+
+		return cassette
+	}
+
+	if path := viper.GetString(flagRecordCassette); path != "" {
+		cassette = transport.NewRecorder(http.DefaultTransport, path)
+
+		return cassette
+	}
+
+	return http.DefaultTransport
+}
+
+func resetCassette() {
+	cassetteMu.Lock()
+	defer cassetteMu.Unlock()
+
+	cassette = nil
 }
@@ -0,0 +1,272 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const pickerPageSize = 15
+
+// picker fetches the candidates of a flag and asks the user to pick one.
+type picker func(cmd *cobra.Command, opts *Options) (string, error)
+
+// pickers lists the flags that can be picked, in the order they are asked:
+// records and collaborators are those of the domain picked first.
+var pickers = []struct {
+	flag string
+	pick picker
+}{
+	{flag: configDomain, pick: pickDomain},
+	{flag: flagRecordID, pick: pickDelegationSignerRecord},
+	{flag: configCollaboratorID, pick: pickCollaborator},
+}
+
+// markFlagPicked marks the flag name of flags as required, with the value
+// picked from candidates fetched from the API when it is missing on a
+// terminal, see pickMissingFlags. The flag is not needed when any of the
+// flags in unless is set.
+func markFlagPicked(flags *pflag.FlagSet, name string, unless ...string) {
+	if err := flags.SetAnnotation(name, annotationPicked, unless); err != nil {
+		panic(err)
+	}
+}
+
+// pickMissingFlags asks for the value of the flags of cmd marked with
+// markFlagPicked that are not set. Without a terminal to ask on, it fails
+// right away instead.
+func pickMissingFlags(cmd *cobra.Command, opts *Options) error {
+	for _, p := range pickers {
+		f := cmd.Flags().Lookup(p.flag)
+		if f == nil || f.Changed || viper.GetString(p.flag) != "" {
+			continue
+		}
+
+		unless, ok := f.Annotations[annotationPicked]
+		if !ok || anyFlagChanged(cmd, unless) {
+			continue
+		}
+
+		if !isTerminal(opts.Stdin) || !isTerminal(opts.Stdout) {
+			return &usageError{
+				err:         fmt.Errorf("required flag %q not set", "--"+p.flag),
+				commandPath: cmd.CommandPath(),
+			}
+		}
+
+		value, err := p.pick(cmd, opts)
+		if err != nil {
+			return err
+		}
+
+		if err := cmd.Flags().Set(p.flag, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func anyFlagChanged(cmd *cobra.Command, names []string) bool {
+	for _, name := range names {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func pickDomain(cmd *cobra.Command, opts *Options) (string, error) {
+	cfg, err := config.New()
+	if err != nil {
+		return "", err
+	}
+
+	domains, err := listAllDomains(cmd.Context(), opts.createClient(cfg.BaseURL, cfg.AccessToken), cfg.Account)
+	if err != nil {
+		return "", err
+	}
+
+	if len(domains) == 0 {
+		return "", fmt.Errorf("account %s has no domains", cfg.Account)
+	}
+
+	return promptChoice("Domain", domainChoices(domains))
+}
+
+func pickDelegationSignerRecord(cmd *cobra.Command, opts *Options) (string, error) {
+	cfg, err := config.New()
+	if err != nil {
+		return "", err
+	}
+
+	domain := cmd.Flags().Lookup(configDomain).Value.String()
+
+	records, err := listAllDelegationSignerRecords(cmd.Context(), opts.createClient(cfg.BaseURL, cfg.AccessToken), cfg.Account, domain)
+	if err != nil {
+		return "", err
+	}
+
+	if len(records) == 0 {
+		return "", fmt.Errorf("domain %s has no delegation signer records", domain)
+	}
+
+	return promptChoice("Delegation signer record", delegationSignerRecordChoices(records))
+}
+
+func pickCollaborator(cmd *cobra.Command, opts *Options) (string, error) {
+	cfg, err := config.New()
+	if err != nil {
+		return "", err
+	}
+
+	domain := cmd.Flags().Lookup(configDomain).Value.String()
+
+	collaborators, err := listAllCollaborators(cmd.Context(), opts.createClient(cfg.BaseURL, cfg.AccessToken), cfg.Account, domain)
+	if err != nil {
+		return "", err
+	}
+
+	if len(collaborators) == 0 {
+		return "", fmt.Errorf("domain %s has no collaborators", domain)
+	}
+
+	return promptChoice("Collaborator", collaboratorChoices(collaborators))
+}
+
+// choice is a candidate of a picked flag: the user picks it by its label and
+// the flag is set to its value.
+type choice struct {
+	label       string
+	description string
+	value       string
+}
+
+// domainChoices offers domains by name, described by their state.
+func domainChoices(domains []dnsimple.Domain) []choice {
+	choices := make([]choice, 0, len(domains))
+
+	for _, domain := range domains {
+		choices = append(choices, choice{label: domain.Name, description: domain.State, value: domain.Name})
+	}
+
+	return choices
+}
+
+// delegationSignerRecordChoices offers records by ID, described by their
+// keytag and algorithm.
+func delegationSignerRecordChoices(records []dnsimple.DelegationSignerRecord) []choice {
+	choices := make([]choice, 0, len(records))
+
+	for _, record := range records {
+		id := strconv.FormatInt(record.ID, 10)
+
+		choices = append(choices, choice{
+			label:       id,
+			description: fmt.Sprintf("keytag %s, algorithm %s", record.Keytag, record.Algorithm),
+			value:       id,
+		})
+	}
+
+	return choices
+}
+
+// collaboratorChoices offers collaborators by email, since that is how people
+// know them, while the flag takes their ID.
+func collaboratorChoices(collaborators []dnsimple.Collaborator) []choice {
+	choices := make([]choice, 0, len(collaborators))
+
+	for _, collaborator := range collaborators {
+		id := strconv.FormatInt(collaborator.ID, 10)
+
+		choices = append(choices, choice{label: collaborator.UserEmail, description: id, value: id})
+	}
+
+	return choices
+}
+
+// promptChoice asks the user to pick one of choices and returns its value.
+func promptChoice(msg string, choices []choice) (string, error) {
+	labels := make([]string, 0, len(choices))
+	descriptions := make([]string, 0, len(choices))
+
+	for _, c := range choices {
+		labels = append(labels, c.label)
+		descriptions = append(descriptions, c.description)
+	}
+
+	i, err := promptPick(msg, labels, descriptions)
+	if err != nil {
+		return "", err
+	}
+
+	return choices[i].value, nil
+}
+
+// promptPick asks the user to pick one of options, narrowed down by typing,
+// and returns its index. Descriptions are shown next to the options.
+func promptPick(msg string, options, descriptions []string) (int, error) {
+	prompt := &survey.Select{
+		Message:  msg,
+		Options:  options,
+		PageSize: pickerPageSize,
+		Filter:   fuzzyMatch,
+		Description: func(_ string, i int) string {
+			return descriptions[i]
+		},
+	}
+
+	var i int
+
+	if err := survey.AskOne(prompt, &i); err != nil {
+		return 0, err
+	}
+
+	if i < 0 || i >= len(options) {
+		return 0, errors.New("nothing picked")
+	}
+
+	return i, nil
+}
+
+// fuzzyMatch reports whether the characters of filter appear in value in
+// the same order, ignoring case.
+func fuzzyMatch(filter, value string, _ int) bool {
+	value = strings.ToLower(value)
+
+	for _, r := range strings.ToLower(filter) {
+		i := strings.IndexRune(value, r)
+		if i < 0 {
+			return false
+		}
+
+		value = value[i+len(string(r)):]
+	}
+
+	return true
+}
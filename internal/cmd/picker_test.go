@@ -0,0 +1,148 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+)
+
+func TestPickerChoices(t *testing.T) {
+	tests := []struct {
+		name string
+		got  []choice
+		want []choice
+	}{
+		{
+			name: "domains",
+			got: domainChoices([]dnsimple.Domain{
+				{ID: 1, Name: "example.com", State: "registered"},
+				{ID: 2, Name: "example.net", State: "hosted"},
+			}),
+			want: []choice{
+				{label: "example.com", description: "registered", value: "example.com"},
+				{label: "example.net", description: "hosted", value: "example.net"},
+			},
+		},
+		{
+			name: "delegation signer records",
+			got: delegationSignerRecordChoices([]dnsimple.DelegationSignerRecord{
+				{ID: 24, Keytag: "44620", Algorithm: "8"},
+			}),
+			want: []choice{
+				{label: "24", description: "keytag 44620, algorithm 8", value: "24"},
+			},
+		},
+		{
+			// Collaborators are picked by email, but the flag takes their ID.
+			name: "collaborators",
+			got: collaboratorChoices([]dnsimple.Collaborator{
+				{ID: 100, UserEmail: "ops@example.com"},
+				{ID: 101, UserEmail: "dev@example.com"},
+			}),
+			want: []choice{
+				{label: "ops@example.com", description: "100", value: "100"},
+				{label: "dev@example.com", description: "101", value: "101"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("choices = %+v, want %+v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPickedValuesSetFlags(t *testing.T) {
+	opts := &Options{}
+
+	tests := []struct {
+		name    string
+		cmd     []string
+		flag    string
+		choices []choice
+	}{
+		{
+			name:    "domain",
+			cmd:     []string{"domain", "get"},
+			flag:    configDomain,
+			choices: domainChoices([]dnsimple.Domain{{Name: "example.com"}}),
+		},
+		{
+			name:    "delegation signer record",
+			cmd:     []string{"domain", "dsr", "get"},
+			flag:    flagRecordID,
+			choices: delegationSignerRecordChoices([]dnsimple.DelegationSignerRecord{{ID: 24}}),
+		},
+		{
+			name:    "collaborator",
+			cmd:     []string{"domain", "collaborator", "remove"},
+			flag:    configCollaboratorID,
+			choices: collaboratorChoices([]dnsimple.Collaborator{{ID: 100, UserEmail: "ops@example.com"}}),
+		},
+	}
+
+	root := CmdRoot(opts)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, _, err := root.Find(tt.cmd)
+			if err != nil || cmd.Name() != tt.cmd[len(tt.cmd)-1] {
+				t.Fatalf("no command %v: %v", tt.cmd, err)
+			}
+
+			f := cmd.Flags().Lookup(tt.flag)
+			if f == nil {
+				t.Fatalf("%s has no --%s flag", cmd.CommandPath(), tt.flag)
+			}
+
+			if _, ok := f.Annotations[annotationPicked]; !ok {
+				t.Errorf("--%s of %s is not picked", tt.flag, cmd.CommandPath())
+			}
+
+			if err := cmd.Flags().Set(tt.flag, tt.choices[0].value); err != nil {
+				t.Errorf("--%s does not take the picked value %q: %v", tt.flag, tt.choices[0].value, err)
+			}
+		})
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		filter string
+		value  string
+		want   bool
+	}{
+		{filter: "", value: "example.com", want: true},
+		{filter: "exc", value: "example.com", want: true},
+		{filter: "EXC", value: "example.com", want: true},
+		{filter: "ops", value: "ops@example.com", want: true},
+		{filter: "moc", value: "example.com", want: false},
+		{filter: "example.org", value: "example.com", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := fuzzyMatch(tt.filter, tt.value, 0); got != tt.want {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.filter, tt.value, got, tt.want)
+		}
+	}
+}
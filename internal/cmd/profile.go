@@ -0,0 +1,347 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/edsonmichaque/dnsimple-cli/internal/format"
+	"github.com/spf13/viper"
+)
+
+// profileFile returns the file backing the active profile. When no profile
+// file was found, it returns the path where a new one would be written.
+func profileFile() (string, error) {
+	if path := viper.ConfigFileUsed(); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	paths, err := configPaths()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(paths[0], fmt.Sprintf("%s.%s", viper.GetString(flagProfile), defaultConfigFileFormat)), nil
+}
+
+// updateProfile sets and removes keys of the profile stored at path, leaving
+// its other settings untouched. The file is created if it does not exist.
+func updateProfile(path string, set map[string]interface{}, unset ...string) error {
+	current := viper.New()
+	current.SetConfigFile(path)
+
+	if err := current.ReadInConfig(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		var notFound viper.ConfigFileNotFoundError
+		if !errors.As(err, &notFound) {
+			return err
+		}
+	}
+
+	settings := current.AllSettings()
+
+	for _, key := range unset {
+		delete(settings, key)
+	}
+
+	for key, value := range set {
+		settings[key] = value
+	}
+
+	v := viper.New()
+	v.SetConfigPermissions(0o600)
+
+	for key, value := range settings {
+		v.Set(key, value)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	return v.WriteConfigAs(path)
+}
+
+const (
+	profileSourceCurrent = "current-profile file"
+	profileSourceDefault = "default"
+	profileSourceEnv     = "environment variable " + envDNSimpleProfile
+	profileSourceFlag    = "--" + flagProfile + " flag"
+)
+
+var (
+	activeProfile       string
+	activeProfileSource string
+)
+
+// resolveProfile picks the active profile from the --profile flag, the
+// DNSIMPLE_PROFILE environment variable or the current-profile file, in that
+// order, and reports which of them selected it.
+func resolveProfile() (string, string) {
+	if profile != "" {
+		return profile, profileSourceFlag
+	}
+
+	if name := os.Getenv(envDNSimpleProfile); name != "" {
+		return name, profileSourceEnv
+	}
+
+	if name, err := readCurrentProfile(); err == nil && name != "" {
+		return name, profileSourceCurrent
+	}
+
+	return defaultProfile, profileSourceDefault
+}
+
+func currentProfilePath() (string, error) {
+	paths, err := configPaths()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(paths[0], pathCurrentProfile), nil
+}
+
+func readCurrentProfile() (string, error) {
+	path, err := currentProfilePath()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+func writeCurrentProfile(name string) error {
+	path, err := currentProfilePath()
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(name+"\n"), 0o600)
+}
+
+// listProfiles returns the profiles found in the configuration directories.
+// A profile in the user directory shadows one with the same name in the
+// system directory.
+func listProfiles() ([]format.Profile, error) {
+	paths, err := configPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		profiles = make([]format.Profile, 0)
+		seen     = make(map[string]struct{})
+	)
+
+	for _, dir := range paths {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			ext := strings.TrimPrefix(filepath.Ext(entry.Name()), ".")
+			if entry.IsDir() || !isConfigFormat(ext) {
+				continue
+			}
+
+			name := strings.TrimSuffix(entry.Name(), "."+ext)
+			if _, ok := seen[name]; ok {
+				continue
+			}
+
+			seen[name] = struct{}{}
+
+			profiles = append(profiles, format.Profile{
+				Name:   name,
+				Path:   filepath.Join(dir, entry.Name()),
+				Active: name == activeProfile,
+			})
+		}
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+
+	return profiles, nil
+}
+
+func findProfile(name string) (*format.Profile, error) {
+	profiles, err := listProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range profiles {
+		if profiles[i].Name == name {
+			return &profiles[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("profile %s not found", name)
+}
+
+func isConfigFormat(ext string) bool {
+	switch strings.ToLower(ext) {
+	case configFormatJSON, configFormatYAML, configFormatYML, configFormatTOML:
+		return true
+	}
+
+	return false
+}
+
+// profileSecretFile returns the secret file "config" writes for the profile
+// name stored in dir.
+func profileSecretFile(dir, name string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s.%s", name, secretFileExt))
+}
+
+// readProfileFile reads the profile stored at path.
+func readProfileFile(path string) (*viper.Viper, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// transferSecretFile gives the profile dst, stored at dstPath as a copy of
+// the profile src of srcDir, a secret file of its own when src uses the one
+// named after it, moving the file when move is set and copying it
+// otherwise. Other secret files are left shared.
+func transferSecretFile(src, srcDir, dst, dstPath string, move bool) error {
+	v, err := readProfileFile(dstPath)
+	if err != nil {
+		return err
+	}
+
+	secretFile := v.GetString(configSecretFile)
+	if secretFile == "" || secretFile != profileSecretFile(srcDir, src) {
+		return nil
+	}
+
+	dstSecretFile := profileSecretFile(filepath.Dir(dstPath), dst)
+
+	users, err := secretFileUsers(dstSecretFile, "")
+	if err != nil {
+		return err
+	}
+
+	if len(users) != 0 {
+		return fmt.Errorf("secret file %s is used by profile %s", dstSecretFile, strings.Join(users, ", "))
+	}
+
+	if move {
+		err = os.Rename(secretFile, dstSecretFile)
+	} else {
+		err = copySecretFile(secretFile, dstSecretFile)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return updateProfile(dstPath, map[string]interface{}{configSecretFile: dstSecretFile})
+}
+
+// removeSecretFile removes secretFile, where profile keeps its access token,
+// unless another profile still reads its token from it.
+func removeSecretFile(secretFile, profile string) error {
+	users, err := secretFileUsers(secretFile, profile)
+	if err != nil {
+		return err
+	}
+
+	if len(users) != 0 {
+		return nil
+	}
+
+	if err := os.Remove(secretFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// secretFileUsers returns the profiles, other than except, whose access token
+// is stored in secretFile.
+func secretFileUsers(secretFile, except string) ([]string, error) {
+	profiles, err := listProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var users []string
+
+	for _, p := range profiles {
+		if p.Name == except {
+			continue
+		}
+
+		v, err := readProfileFile(p.Path)
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: %w", p.Name, err)
+		}
+
+		if v.GetString(configSecretFile) == secretFile {
+			users = append(users, p.Name)
+		}
+	}
+
+	return users, nil
+}
+
+func copySecretFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(dst, data, 0o600)
+}
@@ -19,33 +19,46 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/dnsimple/dnsimple-go/dnsimple"
 	"github.com/edsonmichaque/dnsimple-cli/internal/config"
+	"golang.org/x/term"
 )
 
-func runConfirm(domain string) bool {
-	confirm := false
-	prompt := &survey.Confirm{
-		Message: fmt.Sprintf("Do you want to delete domain %s?", domain),
-	}
-
-	if err := survey.AskOne(prompt, &confirm); err != nil {
-		return false
-	}
-
-	return confirm
-}
+// accountLookup returns the accounts available to a token, see lookupAccounts.
+type accountLookup func(baseURL, token string) ([]dnsimple.Account, *dnsimple.Account, error)
 
-func promptConfig(c *config.Config) (*config.Config, string, error) {
+// promptConfig asks for the settings of a profile, writing notes to out. When
+// the token is to be kept in an encrypted file, the returned configuration
+// points at secretFile and still holds the access token so that it can be
+// stored.
+func promptConfig(out io.Writer, c *config.Config, secretFile, keyFile string, lookup accountLookup) (*config.Config, string, error) {
 	baseURL := prodBaseURL
 
-	accountID, err := promptAccountID(c.Account)
+	storage, err := promptTokenStorage(tokenStoragePlaintext)
 	if err != nil {
 		return nil, "", err
 	}
 
-	accessToken, err := promptAccessToken(c.AccessToken)
+	var accessToken, tokenCommand string
+
+	switch storage {
+	case tokenStorageCommand:
+		tokenCommand, err = promptTokenCommand(c.TokenCommand)
+	case tokenStorageEncrypted:
+		accessToken, err = promptSecret("Access Token")
+		if err == nil {
+			keyFile, err = promptKeyFile(keyFile)
+		}
+	default:
+		accessToken, err = promptAccessToken(c.AccessToken)
+	}
+
 	if err != nil {
 		return nil, "", err
 	}
@@ -66,6 +79,11 @@ func promptConfig(c *config.Config) (*config.Config, string, error) {
 		}
 	}
 
+	accountID, err := promptAccount(out, c.Account, baseURL, accessToken, tokenCommand, lookup)
+	if err != nil {
+		return nil, "", err
+	}
+
 	fileFormat, err := promptFileFormat(configFormatJSON)
 	if err != nil {
 		return nil, "", err
@@ -81,8 +99,14 @@ func promptConfig(c *config.Config) (*config.Config, string, error) {
 	}
 
 	cfg := config.Config{
-		Account:     accountID,
-		AccessToken: accessToken,
+		Account:      accountID,
+		AccessToken:  accessToken,
+		TokenCommand: tokenCommand,
+	}
+
+	if storage == tokenStorageEncrypted {
+		cfg.SecretFile = secretFile
+		cfg.KeyFile = keyFile
 	}
 
 	if env == envDev {
@@ -96,6 +120,53 @@ func promptConfig(c *config.Config) (*config.Config, string, error) {
 	return &cfg, fileFormat, nil
 }
 
+// promptAccount detects the account from the token: account tokens belong to
+// a single account, while user tokens offer a choice among their accounts.
+// When detection fails, the account ID is asked for. The detected account
+// is reported to out.
+func promptAccount(out io.Writer, value, baseURL, token, tokenCommand string, lookup accountLookup) (string, error) {
+	if tokenCommand != "" {
+		var err error
+
+		token, err = config.CommandBackend{Command: tokenCommand}.Token()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	accounts, own, err := lookup(baseURL, token)
+	if err != nil || len(accounts) == 0 {
+		return promptAccountID(value)
+	}
+
+	if own != nil {
+		fmt.Fprintf(out, "Using account %d (%s) of the access token\n", own.ID, own.Email)
+
+		return strconv.FormatInt(own.ID, 10), nil
+	}
+
+	options := make([]string, 0, len(accounts))
+	ids := make(map[string]string, len(accounts))
+
+	for _, account := range accounts {
+		option := fmt.Sprintf("%d %s", account.ID, account.Email)
+		options = append(options, option)
+		ids[option] = strconv.FormatInt(account.ID, 10)
+	}
+
+	prompt := &survey.Select{
+		Message: "Account",
+		Options: options,
+	}
+
+	var selected string
+	if err := survey.AskOne(prompt, &selected); err != nil {
+		return "", err
+	}
+
+	return ids[selected], nil
+}
+
 func promptAccessToken(value string) (string, error) {
 	prompt := &survey.Input{
 		Message: "Access Token",
@@ -110,6 +181,107 @@ func promptAccessToken(value string) (string, error) {
 	return token, nil
 }
 
+func promptTokenStorage(value string) (string, error) {
+	prompt := &survey.Select{
+		Message: "Access token storage",
+		Options: []string{tokenStoragePlaintext, tokenStorageEncrypted, tokenStorageCommand},
+		Default: value,
+		Description: func(value string, _ int) string {
+			switch value {
+			case tokenStorageEncrypted:
+				return "encrypted with a key file or passphrase"
+			case tokenStorageCommand:
+				return "printed by a command such as 'pass show dnsimple'"
+			default:
+				return "stored in the profile file"
+			}
+		},
+	}
+
+	var storage string
+	if err := survey.AskOne(prompt, &storage); err != nil {
+		return "", err
+	}
+
+	return storage, nil
+}
+
+func promptTokenCommand(value string) (string, error) {
+	prompt := &survey.Input{
+		Message: "Token command",
+		Default: value,
+	}
+
+	var command string
+	if err := survey.AskOne(prompt, &command, survey.WithValidator(survey.Required)); err != nil {
+		return "", err
+	}
+
+	return command, nil
+}
+
+// promptKeyFile asks whether the access token is encrypted with a key file
+// or a passphrase and, for a key file, its path. The path is empty when a
+// passphrase is chosen.
+func promptKeyFile(value string) (string, error) {
+	protection := &survey.Select{
+		Message: "Encrypt the access token with",
+		Options: []string{keyProtectionKeyFile, keyProtectionPassphrase},
+		Default: keyProtectionKeyFile,
+		Description: func(value string, _ int) string {
+			if value == keyProtectionPassphrase {
+				return "asked for whenever the token is needed"
+			}
+
+			return "a random key created on first use"
+		},
+	}
+
+	var method string
+	if err := survey.AskOne(protection, &method); err != nil {
+		return "", err
+	}
+
+	if method == keyProtectionPassphrase {
+		return "", nil
+	}
+
+	prompt := &survey.Input{
+		Message: "Key file",
+		Default: value,
+	}
+
+	var keyFile string
+	if err := survey.AskOne(prompt, &keyFile, survey.WithValidator(survey.Required)); err != nil {
+		return "", err
+	}
+
+	return keyFile, nil
+}
+
+func promptSecret(msg string) (string, error) {
+	prompt := &survey.Password{
+		Message: msg,
+	}
+
+	var secret string
+	if err := survey.AskOne(prompt, &secret); err != nil {
+		return "", err
+	}
+
+	return secret, nil
+}
+
+// promptPassphrase returns the passphrase of the encrypted secret file,
+// taking it from the environment when set.
+func promptPassphrase() (string, error) {
+	if passphrase := os.Getenv(envDNSimplePassphrase); passphrase != "" {
+		return passphrase, nil
+	}
+
+	return promptSecret("Passphrase")
+}
+
 func promptAccountID(value string) (string, error) {
 	prompt := &survey.Input{
 		Message: "Account ID",
@@ -182,3 +354,27 @@ func promptConfirmation(msg string, value bool) (bool, error) {
 
 	return confirmation, nil
 }
+
+// isTerminal reports whether v is an interactive terminal, such as the
+// stdin or stdout of a shell session.
+func isTerminal(v interface{}) bool {
+	f, ok := v.(*os.File)
+
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// promptTypedConfirmation asks the user to type want to confirm msg, which
+// is harder to do by mistake than answering yes.
+func promptTypedConfirmation(msg, want string) (bool, error) {
+	prompt := &survey.Input{
+		Message: fmt.Sprintf("%s Type %s to confirm:", msg, want),
+	}
+
+	var answer string
+
+	if err := survey.AskOne(prompt, &answer); err != nil {
+		return false, err
+	}
+
+	return strings.TrimSpace(answer) == want, nil
+}
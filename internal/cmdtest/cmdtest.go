@@ -0,0 +1,215 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cmdtest runs the commands of the CLI in-process against a fake
+// DNSimple API and compares their output with golden files.
+package cmdtest
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/edsonmichaque/dnsimple-cli/internal/api"
+	"github.com/edsonmichaque/dnsimple-cli/internal/cmd"
+	"github.com/spf13/viper"
+)
+
+// BaseURL replaces the URL of the fake API in the output of commands, so that
+// golden files do not depend on the port it listens on.
+const BaseURL = "https://api.dnsimple.test"
+
+// Account and Token are the account and access token commands run with
+// unless the arguments override them.
+const (
+	Account = "1010"
+	Token   = "test-token"
+)
+
+// The URLs of the production and sandbox APIs.
+const (
+	productionURL = "https://api.dnsimple.com"
+	sandboxURL    = "https://api.sandbox.dnsimple.com"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// environment lists the variables cleared so that the configuration of the
+// machine running the tests does not leak into them.
+var environment = []string{
+	"DNSIMPLE_ACCESS_TOKEN",
+	"DNSIMPLE_ACCOUNT",
+	"DNSIMPLE_BASE_URL",
+	"DNSIMPLE_CACHE_TTL",
+	"DNSIMPLE_CONFIG_FILE",
+	"DNSIMPLE_OUTPUT",
+	"DNSIMPLE_PROFILE",
+	"DNSIMPLE_SANDBOX",
+}
+
+// homes holds the configuration and cache directories of each running test,
+// so that the commands a test runs share their profiles and cached
+// responses.
+var homes = struct {
+	sync.Mutex
+	dirs map[testing.TB]home
+}{dirs: make(map[testing.TB]home)}
+
+type home struct {
+	config string
+	cache  string
+}
+
+// Result is the outcome of running a command.
+type Result struct {
+	Stdout   string
+	Stderr   string
+	Err      error
+	ExitCode int
+}
+
+// Run runs the command line args against server with the configuration and
+// cache directories of the test, empty when it starts, and no input. The API
+// is reached with Account and Token, and failed requests are not retried.
+func Run(t testing.TB, server *Server, args ...string) Result {
+	t.Helper()
+
+	return RunWithInput(t, server, "", args...)
+}
+
+// RunWithInput is like Run, with stdin reading from input.
+func RunWithInput(t testing.TB, server *Server, input string, args ...string) Result {
+	t.Helper()
+
+	return run(t, server, input, io.Discard, args...)
+}
+
+// RunTee is like Run, with what the command writes to stderr also written to
+// tee as the command runs, so that a test can react to it.
+func RunTee(t testing.TB, server *Server, tee io.Writer, args ...string) Result {
+	t.Helper()
+
+	return run(t, server, "", tee, args...)
+}
+
+func run(t testing.TB, server *Server, input string, tee io.Writer, args ...string) Result {
+	t.Helper()
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	dirs := testHome(t)
+	t.Setenv("XDG_CONFIG_HOME", dirs.config)
+	t.Setenv("XDG_CACHE_HOME", dirs.cache)
+
+	for _, name := range environment {
+		t.Setenv(name, "")
+	}
+
+	opts, err := cmd.NewOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	opts.Stdin = strings.NewReader(input)
+	opts.Stdout = &stdout
+	opts.Stderr = io.MultiWriter(&stderr, tee)
+
+	// Commands choosing the production or sandbox API, such as "config init
+	// --env sandbox", reach server instead.
+	build := opts.ClientBuilder
+	opts.ClientBuilder = func(url, token string) *api.Client {
+		if url == "" || url == productionURL || url == sandboxURL {
+			url = server.URL
+		}
+
+		return build(url, token)
+	}
+
+	global := []string{
+		"--base-url", server.URL,
+		"--access-token", Token,
+		"--account", Account,
+		"--max-retries", "0",
+	}
+
+	err = cmd.Execute(context.Background(), opts, append(global, args...))
+
+	return Result{
+		Stdout:   strings.ReplaceAll(stdout.String(), server.URL, BaseURL),
+		Stderr:   strings.ReplaceAll(stderr.String(), server.URL, BaseURL),
+		Err:      err,
+		ExitCode: cmd.ExitCode(err),
+	}
+}
+
+// AssertGolden compares got with the file testdata/<name>.golden. Running
+// the tests with -update rewrites the file instead.
+func AssertGolden(t testing.TB, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v; run the tests with -update to create it", err)
+	}
+
+	if got != string(want) {
+		t.Errorf("output does not match %s; run the tests with -update to accept it\n--- want\n%s\n--- got\n%s", path, want, got)
+	}
+}
+
+// testHome returns the directories of t, creating them on first use.
+func testHome(t testing.TB) home {
+	homes.Lock()
+	defer homes.Unlock()
+
+	if dirs, ok := homes.dirs[t]; ok {
+		return dirs
+	}
+
+	dirs := home{config: t.TempDir(), cache: t.TempDir()}
+	homes.dirs[t] = dirs
+
+	t.Cleanup(func() {
+		homes.Lock()
+		delete(homes.dirs, t)
+		homes.Unlock()
+	})
+
+	return dirs
+}
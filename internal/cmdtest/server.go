@@ -0,0 +1,140 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmdtest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+)
+
+// Server is a fake DNSimple API answering each request with the fixture
+// registered for its method and path. Requests without a fixture go to
+// Fallback, or get a 404.
+type Server struct {
+	*httptest.Server
+
+	// Fallback, when set, answers the requests without a fixture.
+	Fallback http.Handler
+
+	t        testing.TB
+	mu       sync.Mutex
+	fixtures map[string]fixture
+	requests []Request
+}
+
+// Request is a request received by a Server.
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Body   string
+}
+
+type fixture struct {
+	status int
+	body   []byte
+}
+
+// NewServer starts a Server, which is closed when the test finishes.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{
+		t:        t,
+		fixtures: make(map[string]fixture),
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// Handle answers the requests to method and path, without the query, with
+// status and the JSON body.
+func (s *Server) Handle(method, path string, status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fixtures[method+" "+path] = fixture{status: status, body: []byte(body)}
+}
+
+// HandleFile is like Handle, with the body read from file.
+func (s *Server) HandleFile(method, path string, status int, file string) {
+	s.t.Helper()
+
+	body, err := os.ReadFile(file)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+
+	s.Handle(method, path, status, string(body))
+}
+
+// Requests returns the requests received so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Body:   string(body),
+	})
+	f, ok := s.fixtures[r.Method+" "+r.URL.Path]
+	s.mu.Unlock()
+
+	if !ok && s.Fallback != nil {
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		s.Fallback.ServeHTTP(w, r)
+
+		return
+	}
+
+	if !ok {
+		f = fixture{
+			status: http.StatusNotFound,
+			body:   []byte(fmt.Sprintf(`{"message":"no fixture for %s %s"}`, r.Method, r.URL.Path)),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(f.status)
+
+	if len(f.body) != 0 {
+		_, _ = w.Write(f.body)
+	}
+}
@@ -27,38 +27,63 @@ const (
 	BaseURLSandbox    = "https://api.sandbox.dnsimple.com"
 )
 
+// AccountResolver, when set, is called by New to obtain the account of a
+// configuration, such as the IDs of the accounts named by email.
+var AccountResolver func(cfg *Config) (string, error)
+
 func New() (*Config, error) {
 	return NewWithValidation(true)
 }
 
 func NewWithValidation(validate bool) (*Config, error) {
+	return NewFromViper(viper.GetViper(), validate)
+}
+
+func NewFromViper(v *viper.Viper, validate bool) (*Config, error) {
 	var cfg Config
-	if err := viper.Unmarshal(&cfg); err != nil {
+	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, err
 	}
 
+	if cfg.Sandbox {
+		cfg.BaseURL = BaseURLSandbox
+	}
+
 	if validate {
-		if err := cfg.Validate(); err != nil {
+		if err := cfg.ResolveAccessToken(); err != nil {
 			return nil, err
 		}
-	}
 
-	if cfg.Sandbox {
-		cfg.BaseURL = BaseURLSandbox
+		if AccountResolver != nil && cfg.AccessToken != "" {
+			account, err := AccountResolver(&cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			cfg.Account = account
+		}
+
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
 	}
 
 	return &cfg, nil
 }
 
 type Config struct {
-	Account     string `mapstructure:"account"`
-	Sandbox     bool   `mapstructure:"sandbox"`
-	AccessToken string `mapstructure:"access-token"`
-	BaseURL     string `mapstructure:"base-url"`
+	Account      string `mapstructure:"account"`
+	Sandbox      bool   `mapstructure:"sandbox"`
+	AccessToken  string `mapstructure:"access-token"`
+	BaseURL      string `mapstructure:"base-url"`
+	TokenCommand string `mapstructure:"token-command"`
+	SecretFile   string `mapstructure:"secret-file"`
+	KeyFile      string `mapstructure:"key-file"`
+	AllAccounts  bool   `mapstructure:"all-accounts"`
 }
 
 func (c Config) Validate() error {
-	if c.Account == "" {
+	if c.Account == "" && !c.AllAccounts {
 		return errors.New("account id is required")
 	}
 
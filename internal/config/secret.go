@@ -0,0 +1,320 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	secretFileVersion = 1
+	secretKDFNone     = "none"
+	secretKDFScrypt   = "scrypt"
+	secretKeySize     = 32
+	secretSaltSize    = 16
+	secretKeyHeader   = "# dnsimple secret key, keep this file private"
+
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// PassphraseFunc is called to obtain the passphrase of an encrypted secret
+// file that is not protected by a key file.
+var PassphraseFunc func() (string, error)
+
+// SecretBackend provides the access token when it is not stored in the
+// profile file itself.
+type SecretBackend interface {
+	Token() (string, error)
+}
+
+// SecretBackend returns the backend configured for the profile, or nil when
+// the access token is stored in the profile file.
+func (c Config) SecretBackend() SecretBackend {
+	if c.TokenCommand != "" {
+		return CommandBackend{Command: c.TokenCommand}
+	}
+
+	if c.SecretFile != "" {
+		return EncryptedFileBackend{Path: c.SecretFile, KeyFile: c.KeyFile}
+	}
+
+	return nil
+}
+
+// ResolveAccessToken fills in the access token from the secret backend when
+// it is not set explicitly.
+func (c *Config) ResolveAccessToken() error {
+	if c.AccessToken != "" {
+		return nil
+	}
+
+	backend := c.SecretBackend()
+	if backend == nil {
+		return nil
+	}
+
+	token, err := backend.Token()
+	if err != nil {
+		return err
+	}
+
+	c.AccessToken = token
+
+	return nil
+}
+
+// CommandBackend runs an external command, such as "pass show dnsimple", and
+// uses the first line of its output as the access token.
+type CommandBackend struct {
+	Command string
+}
+
+func (b CommandBackend) Token() (string, error) {
+	shell, flag := "/bin/sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+
+	stderr := new(bytes.Buffer)
+
+	// #nosec G204 -- the command comes from the user's own configuration
+	cmd := exec.Command(shell, flag, b.Command)
+	cmd.Stderr = stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("token command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	token := strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
+	if token == "" {
+		return "", errors.New("token command returned an empty token")
+	}
+
+	return token, nil
+}
+
+// EncryptedFileBackend keeps the access token in a file encrypted with
+// AES-256-GCM. The key is read from KeyFile or, when no key file is set,
+// derived from a passphrase with scrypt.
+type EncryptedFileBackend struct {
+	Path    string
+	KeyFile string
+}
+
+type secretFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Salt       string `json:"salt,omitempty"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+func (b EncryptedFileBackend) Token() (string, error) {
+	data, err := os.ReadFile(b.Path)
+	if err != nil {
+		return "", err
+	}
+
+	var f secretFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return "", fmt.Errorf("invalid secret file %s: %w", b.Path, err)
+	}
+
+	if f.Version != secretFileVersion {
+		return "", fmt.Errorf("unsupported secret file version %d", f.Version)
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(f.Salt)
+	if err != nil {
+		return "", err
+	}
+
+	nonce, err := base64.StdEncoding.DecodeString(f.Nonce)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(f.Ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := b.key(f.KDF, salt, false)
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", errors.New("could not decrypt secret file, wrong key or passphrase")
+	}
+
+	return string(plaintext), nil
+}
+
+// Store encrypts token and writes it to the secret file. When a key file is
+// configured but does not exist yet, a new random key is written to it.
+func (b EncryptedFileBackend) Store(token string) error {
+	kdf := secretKDFNone
+	if b.KeyFile == "" {
+		kdf = secretKDFScrypt
+	}
+
+	var salt []byte
+
+	if kdf == secretKDFScrypt {
+		salt = make([]byte, secretSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+	}
+
+	key, err := b.key(kdf, salt, true)
+	if err != nil {
+		return err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(secretFile{
+		Version:    secretFileVersion,
+		KDF:        kdf,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce, []byte(token), nil)),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(b.Path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(b.Path, data, 0o600)
+}
+
+func (b EncryptedFileBackend) key(kdf string, salt []byte, create bool) ([]byte, error) {
+	switch kdf {
+	case secretKDFNone:
+		if b.KeyFile == "" {
+			return nil, errors.New("secret file requires a key file")
+		}
+
+		return readKeyFile(b.KeyFile, create)
+	case secretKDFScrypt:
+		if PassphraseFunc == nil {
+			return nil, errors.New("secret file requires a passphrase")
+		}
+
+		passphrase, err := PassphraseFunc()
+		if err != nil {
+			return nil, err
+		}
+
+		if passphrase == "" {
+			return nil, errors.New("passphrase is required")
+		}
+
+		return scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, secretKeySize)
+	default:
+		return nil, fmt.Errorf("unsupported key derivation %q", kdf)
+	}
+}
+
+// readKeyFile reads a key file written by a previous call, generating it
+// first when create is set and the file does not exist.
+func readKeyFile(path string, create bool) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && create {
+		key := make([]byte, secretKeySize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+
+		content := secretKeyHeader + "\n" + base64.StdEncoding.EncodeToString(key) + "\n"
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return nil, err
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			return nil, err
+		}
+
+		return key, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, err := base64.StdEncoding.DecodeString(line)
+		if err != nil || len(key) != secretKeySize {
+			return nil, fmt.Errorf("invalid key file %s", path)
+		}
+
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("invalid key file %s", path)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
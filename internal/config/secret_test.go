@@ -0,0 +1,244 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/edsonmichaque/dnsimple-cli/internal/config"
+)
+
+const token = "dnsimple-test-token"
+
+func TestEncryptedFileBackendKeyFile(t *testing.T) {
+	dir := t.TempDir()
+	backend := config.EncryptedFileBackend{
+		Path:    filepath.Join(dir, "default.secret"),
+		KeyFile: filepath.Join(dir, "secret.key"),
+	}
+
+	if err := backend.Store(token); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{backend.Path, backend.KeyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+			t.Errorf("%s has mode %v, want 0600", path, info.Mode().Perm())
+		}
+	}
+
+	data, err := os.ReadFile(backend.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(data), token) {
+		t.Error("secret file contains the token in clear")
+	}
+
+	got, err := backend.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != token {
+		t.Errorf("Token() = %q, want %q", got, token)
+	}
+}
+
+func TestEncryptedFileBackendWrongKeyFile(t *testing.T) {
+	dir := t.TempDir()
+	backend := config.EncryptedFileBackend{
+		Path:    filepath.Join(dir, "default.secret"),
+		KeyFile: filepath.Join(dir, "secret.key"),
+	}
+
+	if err := backend.Store(token); err != nil {
+		t.Fatal(err)
+	}
+
+	other := config.EncryptedFileBackend{
+		Path:    filepath.Join(dir, "other.secret"),
+		KeyFile: filepath.Join(dir, "other.key"),
+	}
+
+	if err := other.Store(token); err != nil {
+		t.Fatal(err)
+	}
+
+	backend.KeyFile = other.KeyFile
+
+	if _, err := backend.Token(); err == nil || !strings.Contains(err.Error(), "wrong key") {
+		t.Errorf("Token() error = %v, want a wrong key error", err)
+	}
+}
+
+func TestEncryptedFileBackendPassphrase(t *testing.T) {
+	passphrase := "correct horse battery staple"
+	setPassphrase(t, &passphrase)
+
+	backend := config.EncryptedFileBackend{Path: filepath.Join(t.TempDir(), "default.secret")}
+
+	if err := backend.Store(token); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := backend.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != token {
+		t.Errorf("Token() = %q, want %q", got, token)
+	}
+
+	passphrase = "wrong"
+
+	if _, err := backend.Token(); err == nil || !strings.Contains(err.Error(), "wrong key or passphrase") {
+		t.Errorf("Token() with a wrong passphrase: error = %v", err)
+	}
+
+	config.PassphraseFunc = nil
+
+	if _, err := backend.Token(); err == nil {
+		t.Error("Token() without a passphrase succeeded")
+	}
+}
+
+func TestEncryptedFileBackendTampered(t *testing.T) {
+	dir := t.TempDir()
+	backend := config.EncryptedFileBackend{
+		Path:    filepath.Join(dir, "default.secret"),
+		KeyFile: filepath.Join(dir, "secret.key"),
+	}
+
+	if err := backend.Store(token); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(backend.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var f map[string]interface{}
+	if err := json.Unmarshal(data, &f); err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(f["ciphertext"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext[0] ^= 0xff
+	f["ciphertext"] = base64.StdEncoding.EncodeToString(ciphertext)
+
+	if data, err = json.Marshal(f); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(backend.Path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := backend.Token(); err == nil {
+		t.Error("Token() of a tampered secret file succeeded")
+	}
+}
+
+func TestCommandBackend(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the commands are written for a POSIX shell")
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		want    string
+		wantErr string
+	}{
+		{name: "first line", command: "echo ' " + token + " '; echo second", want: token},
+		{name: "failure", command: "echo locked >&2; exit 3", wantErr: "locked"},
+		{name: "empty", command: "true", wantErr: "empty token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := config.CommandBackend{Command: tt.command}.Token()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Token() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Token() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveAccessToken(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the command is written for a POSIX shell")
+	}
+
+	cfg := config.Config{TokenCommand: "echo " + token}
+	if err := cfg.ResolveAccessToken(); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.AccessToken != token {
+		t.Errorf("AccessToken = %q, want %q", cfg.AccessToken, token)
+	}
+
+	cfg = config.Config{AccessToken: "explicit", TokenCommand: "exit 1"}
+	if err := cfg.ResolveAccessToken(); err != nil || cfg.AccessToken != "explicit" {
+		t.Errorf("explicit token: AccessToken = %q, error = %v", cfg.AccessToken, err)
+	}
+}
+
+// setPassphrase makes the backends read the passphrase from p for the
+// duration of the test.
+func setPassphrase(t *testing.T, p *string) {
+	t.Helper()
+
+	saved := config.PassphraseFunc
+	t.Cleanup(func() { config.PassphraseFunc = saved })
+
+	config.PassphraseFunc = func() (string, error) {
+		return *p, nil
+	}
+}
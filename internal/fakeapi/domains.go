@@ -0,0 +1,308 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+)
+
+var domainName = regexp.MustCompile(`^(?i)([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// nameServers are the name servers of the zones of hosted domains.
+var nameServers = []string{
+	"ns1.dnsimple.com",
+	"ns2.dnsimple-edge.net",
+	"ns3.dnsimple.com",
+	"ns4.dnsimple-edge.org",
+}
+
+func (s *Server) routeDomains(w http.ResponseWriter, r *http.Request, accountID string, parts []string) {
+	account := s.account(accountID)
+	if account == nil {
+		notFound(w, fmt.Sprintf("Account `%s` not found", accountID))
+
+		return
+	}
+
+	if len(parts) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			s.listDomains(w, r, account.ID)
+		case http.MethodPost:
+			s.postDomain(w, r, account.ID)
+		default:
+			methodNotAllowed(w)
+		}
+
+		return
+	}
+
+	d := s.findDomain(account.ID, parts[0])
+	if d == nil {
+		notFound(w, fmt.Sprintf("Domain `%s` not found", parts[0]))
+
+		return
+	}
+
+	switch {
+	case len(parts) == 1:
+		s.routeDomain(w, r, d)
+	case parts[1] == "collaborators":
+		s.routeCollaborators(w, r, d, parts[2:])
+	case parts[1] == "ds_records":
+		s.routeDSRecords(w, r, d, parts[2:])
+	case parts[1] == "dnssec" && len(parts) == 2:
+		s.routeDNSSEC(w, r, d)
+	default:
+		notFound(w, "Endpoint not found")
+	}
+}
+
+func (s *Server) listDomains(w http.ResponseWriter, r *http.Request, accountID int64) {
+	domains := make([]dnsimple.Domain, 0, len(s.domains[accountID]))
+	for _, d := range s.domains[accountID] {
+		domains = append(domains, d.Domain)
+	}
+
+	domains = nameLike(r, domains, func(d dnsimple.Domain) string { return d.Name })
+
+	if registrant := r.URL.Query().Get("registrant_id"); registrant != "" {
+		filtered := domains[:0]
+
+		for _, d := range domains {
+			if strconv.FormatInt(d.RegistrantID, 10) == registrant {
+				filtered = append(filtered, d)
+			}
+		}
+
+		domains = filtered
+	}
+
+	paginate(w, r, domains)
+}
+
+func (s *Server) postDomain(w http.ResponseWriter, r *http.Request, accountID int64) {
+	var attrs dnsimple.Domain
+	if !decode(w, r, &attrs) {
+		return
+	}
+
+	d, fields := s.createDomain(accountID, attrs.Name)
+	if fields != nil {
+		writeError(w, http.StatusBadRequest, "Validation failed", fields)
+
+		return
+	}
+
+	writeData(w, http.StatusCreated, d.Domain)
+}
+
+// createDomain adds a hosted domain with a zone holding the SOA and NS
+// records, or returns the validation errors of its name.
+func (s *Server) createDomain(accountID int64, name string) (*domain, map[string][]string) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+
+	switch {
+	case name == "":
+		return nil, map[string][]string{"name": {"can't be blank"}}
+	case !domainName.MatchString(name):
+		return nil, map[string][]string{"name": {"is an invalid domain"}}
+	case s.findDomain(accountID, name) != nil:
+		return nil, map[string][]string{"name": {"has already been taken"}}
+	}
+
+	now := s.timestamp()
+	d := &domain{
+		Domain: dnsimple.Domain{
+			ID:          s.id(),
+			AccountID:   accountID,
+			Name:        name,
+			UnicodeName: name,
+			State:       "hosted",
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		},
+	}
+
+	d.records = append(d.records, s.newRecord(name, dnsimple.ZoneRecord{
+		Type:         "SOA",
+		Content:      fmt.Sprintf("%s admin.dnsimple.com 1 7200 120 2419200 300", nameServers[0]),
+		TTL:          3600,
+		SystemRecord: true,
+	}))
+
+	for _, ns := range nameServers {
+		d.records = append(d.records, s.newRecord(name, dnsimple.ZoneRecord{
+			Type:         "NS",
+			Content:      ns,
+			TTL:          3600,
+			SystemRecord: true,
+		}))
+	}
+
+	s.domains[accountID] = append(s.domains[accountID], d)
+
+	return d, nil
+}
+
+func (s *Server) routeDomain(w http.ResponseWriter, r *http.Request, d *domain) {
+	switch r.Method {
+	case http.MethodGet:
+		writeData(w, http.StatusOK, d.Domain)
+	case http.MethodDelete:
+		domains := s.domains[d.AccountID]
+
+		for i := range domains {
+			if domains[i] == d {
+				s.domains[d.AccountID] = append(domains[:i:i], domains[i+1:]...)
+
+				break
+			}
+		}
+
+		noContent(w)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) routeCollaborators(w http.ResponseWriter, r *http.Request, d *domain, parts []string) {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		paginate(w, r, d.collaborators)
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		var attrs dnsimple.CollaboratorAttributes
+		if !decode(w, r, &attrs) {
+			return
+		}
+
+		if !strings.Contains(attrs.Email, "@") {
+			writeError(w, http.StatusBadRequest, "Validation failed", map[string][]string{"email": {"is invalid"}})
+
+			return
+		}
+
+		now := s.timestamp()
+		collaborator := dnsimple.Collaborator{
+			ID:         s.id(),
+			DomainID:   d.ID,
+			DomainName: d.Name,
+			UserEmail:  attrs.Email,
+			Invitation: true,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+
+		d.collaborators = append(d.collaborators, collaborator)
+
+		writeData(w, http.StatusCreated, collaborator)
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		for i := range d.collaborators {
+			if strconv.FormatInt(d.collaborators[i].ID, 10) == parts[0] {
+				d.collaborators = append(d.collaborators[:i:i], d.collaborators[i+1:]...)
+				noContent(w)
+
+				return
+			}
+		}
+
+		notFound(w, fmt.Sprintf("Collaborator `%s` not found", parts[0]))
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) routeDSRecords(w http.ResponseWriter, r *http.Request, d *domain, parts []string) {
+	if len(parts) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			paginate(w, r, d.dsRecords)
+		case http.MethodPost:
+			var attrs dnsimple.DelegationSignerRecord
+			if !decode(w, r, &attrs) {
+				return
+			}
+
+			if attrs.Algorithm == "" {
+				writeError(w, http.StatusBadRequest, "Validation failed", map[string][]string{"algorithm": {"can't be blank"}})
+
+				return
+			}
+
+			now := s.timestamp()
+			attrs.ID = s.id()
+			attrs.DomainID = d.ID
+			attrs.CreatedAt = now
+			attrs.UpdatedAt = now
+
+			d.dsRecords = append(d.dsRecords, attrs)
+
+			writeData(w, http.StatusCreated, attrs)
+		default:
+			methodNotAllowed(w)
+		}
+
+		return
+	}
+
+	for i := range d.dsRecords {
+		if strconv.FormatInt(d.dsRecords[i].ID, 10) != parts[0] {
+			continue
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			writeData(w, http.StatusOK, d.dsRecords[i])
+		case http.MethodDelete:
+			d.dsRecords = append(d.dsRecords[:i:i], d.dsRecords[i+1:]...)
+			noContent(w)
+		default:
+			methodNotAllowed(w)
+		}
+
+		return
+	}
+
+	notFound(w, fmt.Sprintf("Delegation signer record `%s` not found", parts[0]))
+}
+
+func (s *Server) routeDNSSEC(w http.ResponseWriter, r *http.Request, d *domain) {
+	switch r.Method {
+	case http.MethodGet:
+		writeData(w, http.StatusOK, dnsimple.Dnssec{Enabled: d.dnssec})
+	case http.MethodPost:
+		d.dnssec = true
+		writeData(w, http.StatusCreated, dnsimple.Dnssec{Enabled: true})
+	case http.MethodDelete:
+		if !d.dnssec {
+			writeError(w, http.StatusPreconditionRequired, "DNSSEC cannot be disabled because it is not enabled", nil)
+
+			return
+		}
+
+		d.dnssec = false
+		noContent(w)
+	default:
+		methodNotAllowed(w)
+	}
+}
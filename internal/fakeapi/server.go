@@ -0,0 +1,368 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package fakeapi is a stateful, in-memory implementation of the parts of
+// the DNSimple API v2 used by the CLI: accounts, whoami, domains,
+// collaborators, delegation signer records, DNSSEC, zones and records, plus
+// an OAuth authorization flow that grants every request.
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+)
+
+const (
+	defaultPerPage = 30
+	maxPerPage     = 100
+	rateLimit      = 2400
+
+	// Token is the access token issued by the OAuth flow. Any bearer token
+	// is accepted.
+	Token = "fake-access-token"
+)
+
+// Server serves the fake API. The zero value is not usable, see New.
+type Server struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	nextID   int64
+	accounts []dnsimple.Account
+	domains  map[int64][]*domain
+}
+
+type domain struct {
+	dnsimple.Domain
+
+	records       []dnsimple.ZoneRecord
+	collaborators []dnsimple.Collaborator
+	dsRecords     []dnsimple.DelegationSignerRecord
+	dnssec        bool
+}
+
+// New returns a Server holding the given accounts and no domains. With a
+// single account, whoami answers like for an account token, otherwise like
+// for a user token.
+func New(accounts ...dnsimple.Account) *Server {
+	s := &Server{
+		now:     time.Now,
+		nextID:  1,
+		domains: make(map[int64][]*domain),
+	}
+
+	for _, account := range accounts {
+		s.AddAccount(account)
+	}
+
+	return s
+}
+
+// AddAccount adds an account, filling in its timestamps.
+func (s *Server) AddAccount(account dnsimple.Account) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if account.CreatedAt == "" {
+		account.CreatedAt = s.timestamp()
+		account.UpdatedAt = account.CreatedAt
+	}
+
+	s.accounts = append(s.accounts, account)
+}
+
+// AddDomain creates a hosted domain and its zone in the account.
+func (s *Server) AddDomain(accountID int64, name string) (*dnsimple.Domain, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.account(strconv.FormatInt(accountID, 10)) == nil {
+		return nil, fmt.Errorf("account %d not found", accountID)
+	}
+
+	d, fields := s.createDomain(accountID, name)
+	if fields != nil {
+		return nil, fmt.Errorf("invalid domain %s: %v", name, fields)
+	}
+
+	domain := d.Domain
+
+	return &domain, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rateLimit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(rateLimit-1))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(s.now().Add(time.Hour).Unix(), 10))
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	if r.URL.Path == "/oauth/authorize" {
+		s.authorize(w, r)
+
+		return
+	}
+
+	if len(parts) < 2 || parts[0] != "v2" {
+		notFound(w, "Endpoint not found")
+
+		return
+	}
+
+	if r.Method == http.MethodPost && r.URL.Path == "/v2/oauth/access_token" {
+		s.accessToken(w, r)
+
+		return
+	}
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		writeError(w, http.StatusUnauthorized, "Authentication failed", nil)
+
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch parts = parts[1:]; {
+	case len(parts) == 1 && parts[0] == "whoami":
+		s.whoami(w, r)
+	case len(parts) == 1 && parts[0] == "accounts":
+		s.listAccounts(w, r)
+	case len(parts) >= 2 && parts[1] == "domains":
+		s.routeDomains(w, r, parts[0], parts[2:])
+	case len(parts) >= 2 && parts[1] == "zones":
+		s.routeZones(w, r, parts[0], parts[2:])
+	default:
+		notFound(w, "Endpoint not found")
+	}
+}
+
+// authorize grants every authorization request right away by redirecting to
+// the redirect URI with a code.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	redirect, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirect.Scheme == "" {
+		writeError(w, http.StatusBadRequest, "Invalid redirect_uri", nil)
+
+		return
+	}
+
+	values := redirect.Query()
+	values.Set("code", "fake-authorization-code")
+	values.Set("state", query.Get("state"))
+	redirect.RawQuery = values.Encode()
+
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (s *Server) accessToken(w http.ResponseWriter, r *http.Request) {
+	var req dnsimple.ExchangeAuthorizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error":             "invalid_request",
+			"error_description": "Invalid authorization code",
+		})
+
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token := dnsimple.AccessToken{Token: Token, Type: "bearer"}
+	if len(s.accounts) != 0 {
+		token.AccountID = s.accounts[0].ID
+	}
+
+	writeJSON(w, http.StatusOK, token)
+}
+
+func (s *Server) whoami(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+
+		return
+	}
+
+	var whoami dnsimple.WhoamiData
+
+	switch len(s.accounts) {
+	case 0:
+	case 1:
+		whoami.Account = &s.accounts[0]
+	default:
+		whoami.User = &dnsimple.User{ID: 1, Email: s.accounts[0].Email}
+	}
+
+	writeData(w, http.StatusOK, whoami)
+}
+
+func (s *Server) listAccounts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+
+		return
+	}
+
+	writeData(w, http.StatusOK, s.accounts)
+}
+
+func (s *Server) account(id string) *dnsimple.Account {
+	for i := range s.accounts {
+		if strconv.FormatInt(s.accounts[i].ID, 10) == id {
+			return &s.accounts[i]
+		}
+	}
+
+	return nil
+}
+
+// findDomain returns the domain of the account with the given name or ID.
+func (s *Server) findDomain(accountID int64, identifier string) *domain {
+	for _, d := range s.domains[accountID] {
+		if strings.EqualFold(d.Name, identifier) || strconv.FormatInt(d.ID, 10) == identifier {
+			return d
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) id() int64 {
+	id := s.nextID
+	s.nextID++
+
+	return id
+}
+
+func (s *Server) timestamp() string {
+	return s.now().UTC().Format(time.RFC3339)
+}
+
+// paginate writes the page of items requested by the page and per_page
+// query parameters.
+func paginate[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	query := r.URL.Query()
+
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	perPage, err := strconv.Atoi(query.Get("per_page"))
+	if err != nil || perPage < 1 {
+		perPage = defaultPerPage
+	}
+
+	if perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+
+	totalPages := (len(items) + perPage - 1) / perPage
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	start := (page - 1) * perPage
+	if start > len(items) {
+		start = len(items)
+	}
+
+	end := start + perPage
+	if end > len(items) {
+		end = len(items)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data": items[start:end],
+		"pagination": dnsimple.Pagination{
+			CurrentPage:  page,
+			PerPage:      perPage,
+			TotalPages:   totalPages,
+			TotalEntries: len(items),
+		},
+	})
+}
+
+// nameLike filters items whose name contains the name_like query parameter.
+func nameLike[T any](r *http.Request, items []T, name func(T) string) []T {
+	like := strings.ToLower(r.URL.Query().Get("name_like"))
+	if like == "" {
+		return items
+	}
+
+	filtered := make([]T, 0, len(items))
+
+	for _, item := range items {
+		if strings.Contains(strings.ToLower(name(item)), like) {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return filtered
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON: "+err.Error(), nil)
+
+		return false
+	}
+
+	return true
+}
+
+func writeData(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, map[string]interface{}{"data": data})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string, fields map[string][]string) {
+	body := map[string]interface{}{"message": message}
+	if fields != nil {
+		body["errors"] = fields
+	}
+
+	writeJSON(w, status, body)
+}
+
+func notFound(w http.ResponseWriter, message string) {
+	writeError(w, http.StatusNotFound, message, nil)
+}
+
+func methodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "Method not allowed", nil)
+}
+
+func noContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,160 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package fakeapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/fakeapi"
+)
+
+func newClient(t *testing.T, server *fakeapi.Server) *dnsimple.Client {
+	t.Helper()
+
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+
+	client := dnsimple.NewClient(dnsimple.StaticTokenHTTPClient(context.Background(), "token"))
+	client.BaseURL = ts.URL
+
+	return client
+}
+
+func TestDomainsAndRecords(t *testing.T) {
+	ctx := context.Background()
+	client := newClient(t, fakeapi.New(dnsimple.Account{ID: 1, Email: "dev@example.com"}))
+
+	whoami, err := client.Identity.Whoami(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if whoami.Data.Account == nil || whoami.Data.Account.ID != 1 {
+		t.Fatalf("whoami = %+v, want account 1", whoami.Data)
+	}
+
+	if _, err := client.Domains.CreateDomain(ctx, "1", dnsimple.Domain{Name: "example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Domains.CreateDomain(ctx, "1", dnsimple.Domain{Name: "example.com"})
+
+	var errResp *dnsimple.ErrorResponse
+	if !errors.As(err, &errResp) || errResp.HTTPResponse.StatusCode != http.StatusBadRequest || len(errResp.AttributeErrors["name"]) == 0 {
+		t.Fatalf("creating a duplicate domain: %v, want a validation error", err)
+	}
+
+	name := "www"
+
+	created, err := client.Zones.CreateRecord(ctx, "1", "example.com", dnsimple.ZoneRecordAttributes{
+		Type:    "A",
+		Name:    &name,
+		Content: "203.0.113.10",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := client.Zones.UpdateRecord(ctx, "1", "example.com", created.Data.ID, dnsimple.ZoneRecordAttributes{
+		Content: "198.51.100.7",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if updated.Data.Name != "www" || updated.Data.Content != "198.51.100.7" || updated.Data.TTL != 3600 {
+		t.Errorf("updated record = %+v", updated.Data)
+	}
+
+	records, err := client.Zones.ListRecords(ctx, "1", "example.com", &dnsimple.ZoneRecordListOptions{Type: dnsimple.String("A")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records.Data) != 1 || records.Data[0].ID != created.Data.ID {
+		t.Errorf("A records = %+v, want the created record", records.Data)
+	}
+
+	if _, err := client.Zones.DeleteRecord(ctx, "1", "example.com", created.Data.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Domains.DeleteDomain(ctx, "1", "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Zones.ListRecords(ctx, "1", "example.com", nil)
+	if !errors.As(err, &errResp) || errResp.HTTPResponse.StatusCode != http.StatusNotFound {
+		t.Fatalf("listing the records of a deleted zone: %v, want not found", err)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	server := fakeapi.New(dnsimple.Account{ID: 1})
+
+	for _, name := range []string{"a.com", "b.com", "c.com"} {
+		if _, err := server.AddDomain(1, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := newClient(t, server)
+
+	resp, err := client.Domains.ListDomains(context.Background(), "1", &dnsimple.DomainListOptions{
+		ListOptions: dnsimple.ListOptions{Page: dnsimple.Int(2), PerPage: dnsimple.Int(2)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Data) != 1 || resp.Data[0].Name != "c.com" {
+		t.Errorf("page 2 = %+v, want c.com", resp.Data)
+	}
+
+	if resp.Pagination.TotalPages != 2 || resp.Pagination.TotalEntries != 3 {
+		t.Errorf("pagination = %+v", resp.Pagination)
+	}
+}
+
+func TestDNSSEC(t *testing.T) {
+	ctx := context.Background()
+	server := fakeapi.New(dnsimple.Account{ID: 1})
+
+	if _, err := server.AddDomain(1, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	client := newClient(t, server)
+
+	if _, err := client.Domains.EnableDnssec(ctx, "1", "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := client.Domains.GetDnssec(ctx, "1", "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !status.Data.Enabled {
+		t.Error("DNSSEC is not enabled")
+	}
+}
@@ -0,0 +1,300 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+)
+
+var recordTypes = map[string]bool{
+	"A":     true,
+	"AAAA":  true,
+	"ALIAS": true,
+	"CAA":   true,
+	"CNAME": true,
+	"HINFO": true,
+	"MX":    true,
+	"NAPTR": true,
+	"NS":    true,
+	"PTR":   true,
+	"SPF":   true,
+	"SRV":   true,
+	"SSHFP": true,
+	"TXT":   true,
+	"URL":   true,
+}
+
+// recordAttributes are the attributes of a record in create and update
+// requests. Unlike dnsimple.ZoneRecordAttributes, absent attributes can be
+// told apart from zero values.
+type recordAttributes struct {
+	Type     *string  `json:"type"`
+	Name     *string  `json:"name"`
+	Content  *string  `json:"content"`
+	TTL      *int     `json:"ttl"`
+	Priority *int     `json:"priority"`
+	Regions  []string `json:"regions"`
+}
+
+// AddRecord adds a record to the zone of a domain of the account, filling in
+// its ID and timestamps, and a TTL of an hour when it has none.
+func (s *Server) AddRecord(accountID int64, zone string, record dnsimple.ZoneRecord) (*dnsimple.ZoneRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.findDomain(accountID, zone)
+	if d == nil {
+		return nil, fmt.Errorf("zone %s not found in account %d", zone, accountID)
+	}
+
+	if record.TTL == 0 {
+		record.TTL = 3600
+	}
+
+	record.Type = strings.ToUpper(record.Type)
+	if !recordTypes[record.Type] || record.Content == "" {
+		return nil, fmt.Errorf("invalid %s record %q", record.Type, record.Content)
+	}
+
+	record = s.newRecord(d.Name, record)
+	d.records = append(d.records, record)
+
+	return &record, nil
+}
+
+func (s *Server) routeZones(w http.ResponseWriter, r *http.Request, accountID string, parts []string) {
+	account := s.account(accountID)
+	if account == nil {
+		notFound(w, fmt.Sprintf("Account `%s` not found", accountID))
+
+		return
+	}
+
+	if len(parts) == 0 {
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+
+			return
+		}
+
+		zones := make([]dnsimple.Zone, 0, len(s.domains[account.ID]))
+		for _, d := range s.domains[account.ID] {
+			zones = append(zones, zoneOf(d))
+		}
+
+		paginate(w, r, nameLike(r, zones, func(z dnsimple.Zone) string { return z.Name }))
+
+		return
+	}
+
+	d := s.findDomain(account.ID, parts[0])
+	if d == nil {
+		notFound(w, fmt.Sprintf("Zone `%s` not found", parts[0]))
+
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		writeData(w, http.StatusOK, zoneOf(d))
+	case len(parts) == 1:
+		methodNotAllowed(w)
+	case parts[1] == "records" && len(parts) == 2:
+		s.routeRecords(w, r, d)
+	case parts[1] == "records" && len(parts) == 3:
+		s.routeRecord(w, r, d, parts[2])
+	default:
+		notFound(w, "Endpoint not found")
+	}
+}
+
+func (s *Server) routeRecords(w http.ResponseWriter, r *http.Request, d *domain) {
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		records := nameLike(r, d.records, func(rec dnsimple.ZoneRecord) string { return rec.Name })
+
+		filtered := make([]dnsimple.ZoneRecord, 0, len(records))
+
+		for _, rec := range records {
+			if name, ok := query["name"]; ok && rec.Name != name[0] {
+				continue
+			}
+
+			if typ := query.Get("type"); typ != "" && !strings.EqualFold(rec.Type, typ) {
+				continue
+			}
+
+			filtered = append(filtered, rec)
+		}
+
+		paginate(w, r, filtered)
+	case http.MethodPost:
+		var attrs recordAttributes
+		if !decode(w, r, &attrs) {
+			return
+		}
+
+		rec := dnsimple.ZoneRecord{TTL: 3600}
+		if fields := applyRecordAttributes(&rec, attrs, true); fields != nil {
+			writeError(w, http.StatusBadRequest, "Validation failed", fields)
+
+			return
+		}
+
+		rec = s.newRecord(d.Name, rec)
+		d.records = append(d.records, rec)
+
+		writeData(w, http.StatusCreated, rec)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) routeRecord(w http.ResponseWriter, r *http.Request, d *domain, id string) {
+	for i := range d.records {
+		rec := &d.records[i]
+		if strconv.FormatInt(rec.ID, 10) != id {
+			continue
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			writeData(w, http.StatusOK, rec)
+		case http.MethodPatch:
+			if rec.SystemRecord {
+				writeError(w, http.StatusBadRequest, "System records cannot be updated", nil)
+
+				return
+			}
+
+			var attrs recordAttributes
+			if !decode(w, r, &attrs) {
+				return
+			}
+
+			updated := *rec
+			if fields := applyRecordAttributes(&updated, attrs, false); fields != nil {
+				writeError(w, http.StatusBadRequest, "Validation failed", fields)
+
+				return
+			}
+
+			updated.UpdatedAt = s.timestamp()
+			*rec = updated
+
+			writeData(w, http.StatusOK, rec)
+		case http.MethodDelete:
+			if rec.SystemRecord {
+				writeError(w, http.StatusBadRequest, "System records cannot be deleted", nil)
+
+				return
+			}
+
+			d.records = append(d.records[:i:i], d.records[i+1:]...)
+			noContent(w)
+		default:
+			methodNotAllowed(w)
+		}
+
+		return
+	}
+
+	notFound(w, fmt.Sprintf("Record `%s` not found", id))
+}
+
+// applyRecordAttributes sets the attributes present in attrs on rec and
+// returns the validation errors of the result, if any.
+func applyRecordAttributes(rec *dnsimple.ZoneRecord, attrs recordAttributes, create bool) map[string][]string {
+	if attrs.Type != nil {
+		if !create && !strings.EqualFold(*attrs.Type, rec.Type) {
+			return map[string][]string{"type": {"cannot be changed"}}
+		}
+
+		rec.Type = strings.ToUpper(*attrs.Type)
+	}
+
+	if attrs.Name != nil {
+		rec.Name = *attrs.Name
+	}
+
+	if attrs.Content != nil {
+		rec.Content = *attrs.Content
+	}
+
+	if attrs.TTL != nil {
+		rec.TTL = *attrs.TTL
+	}
+
+	if attrs.Priority != nil {
+		rec.Priority = *attrs.Priority
+	}
+
+	if attrs.Regions != nil {
+		rec.Regions = attrs.Regions
+	}
+
+	fields := make(map[string][]string)
+
+	if !recordTypes[rec.Type] {
+		fields["type"] = append(fields["type"], "is not included in the list")
+	}
+
+	if rec.Content == "" {
+		fields["content"] = append(fields["content"], "can't be blank")
+	}
+
+	if rec.TTL < 1 {
+		fields["ttl"] = append(fields["ttl"], "must be greater than 0")
+	}
+
+	if len(fields) != 0 {
+		return fields
+	}
+
+	return nil
+}
+
+func (s *Server) newRecord(zone string, rec dnsimple.ZoneRecord) dnsimple.ZoneRecord {
+	now := s.timestamp()
+
+	rec.ID = s.id()
+	rec.ZoneID = zone
+	rec.CreatedAt = now
+	rec.UpdatedAt = now
+
+	if rec.Regions == nil {
+		rec.Regions = []string{"global"}
+	}
+
+	return rec
+}
+
+func zoneOf(d *domain) dnsimple.Zone {
+	return dnsimple.Zone{
+		ID:        d.ID,
+		AccountID: d.AccountID,
+		Name:      d.Name,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+)
+
+type AccountItem dnsimple.Account
+
+func (a AccountItem) FormatText(opts *Options) (io.Reader, error) {
+	keys := []string{
+		"id",
+		"email",
+		"plan_identifier",
+		"created_at",
+		"updated_at",
+	}
+
+	values := map[string]interface{}{
+		"id":              a.ID,
+		"email":           a.Email,
+		"plan_identifier": a.PlanIdentifier,
+		"created_at":      a.CreatedAt,
+		"updated_at":      a.UpdatedAt,
+	}
+
+	titles := map[string]string{
+		"id":              "ID",
+		"email":           "Email",
+		"plan_identifier": "Plan",
+		"created_at":      "Created at",
+		"updated_at":      "Updated at",
+	}
+
+	buf := new(bytes.Buffer)
+	for _, v := range keys {
+		buf.WriteString(fmt.Sprintf("%-20s%v\n", titles[v]+":", values[v]))
+	}
+
+	return buf, nil
+}
+
+func (a AccountItem) FormatJSON(opts *Options) (io.Reader, error) {
+	return formatJSON(a, opts)
+}
+
+func (a AccountItem) FormatYAML(opts *Options) (io.Reader, error) {
+	return formatYAML(a, opts)
+}
+
+func (a AccountItem) formatJSON(opts *Options) ([]byte, error) {
+	return json.MarshalIndent(dnsimple.Account(a), "", "  ")
+}
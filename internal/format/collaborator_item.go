@@ -0,0 +1,85 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+)
+
+type CollaboratorItem dnsimple.CollaboratorResponse
+
+func (c CollaboratorItem) FormatText(opts *Options) (io.Reader, error) {
+	keys := []string{
+		"id",
+		"domain_id",
+		"domain_name",
+		"user_id",
+		"user_email",
+		"invitation",
+		"created_at",
+		"updated_at",
+		"accepted_at",
+	}
+
+	values := map[string]interface{}{
+		"id":          c.Data.ID,
+		"domain_id":   c.Data.DomainID,
+		"domain_name": c.Data.DomainName,
+		"user_id":     c.Data.UserID,
+		"user_email":  c.Data.UserEmail,
+		"invitation":  c.Data.Invitation,
+		"created_at":  c.Data.CreatedAt,
+		"updated_at":  c.Data.UpdatedAt,
+		"accepted_at": c.Data.AcceptedAt,
+	}
+
+	titles := map[string]string{
+		"id":          "ID",
+		"domain_id":   "Domain ID",
+		"domain_name": "Domain name",
+		"user_id":     "User ID",
+		"user_email":  "User email",
+		"invitation":  "Invitation",
+		"created_at":  "Created at",
+		"updated_at":  "Updated at",
+		"accepted_at": "Accepted at",
+	}
+
+	buf := new(bytes.Buffer)
+	for _, v := range keys {
+		buf.WriteString(fmt.Sprintf("%-20s%v\n", titles[v]+":", values[v]))
+	}
+
+	return buf, nil
+}
+
+func (c CollaboratorItem) FormatJSON(opts *Options) (io.Reader, error) {
+	return formatJSON(c, opts)
+}
+
+func (c CollaboratorItem) FormatYAML(opts *Options) (io.Reader, error) {
+	return formatYAML(c, opts)
+}
+
+func (c CollaboratorItem) formatJSON(opts *Options) ([]byte, error) {
+	return json.MarshalIndent(c.Data, "", "  ")
+}
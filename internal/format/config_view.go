@@ -0,0 +1,74 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type ConfigValue struct {
+	Key    string      `json:"key"`
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"`
+}
+
+type ConfigView []ConfigValue
+
+func (c ConfigView) FormatJSON(opts *Options) (io.Reader, error) {
+	return formatJSON(c, opts)
+}
+
+func (c ConfigView) FormatYAML(opts *Options) (io.Reader, error) {
+	return formatYAML(c, opts)
+}
+
+func (c ConfigView) FormatTable(_ *Options) (io.Reader, error) {
+	return formatTable(c)
+}
+
+func (c ConfigView) formatJSON(opts *Options) ([]byte, error) {
+	return json.MarshalIndent(c, "", "  ")
+}
+
+func (c ConfigView) formatHeader() []string {
+	return []string{
+		"KEY",
+		"VALUE",
+		"SOURCE",
+	}
+}
+
+func (c ConfigView) formatRows() []map[string]string {
+	data := make([]map[string]string, 0, len(c))
+
+	for i := range c {
+		value := ""
+		if c[i].Value != nil {
+			value = fmt.Sprintf("%v", c[i].Value)
+		}
+
+		data = append(data, map[string]string{
+			"KEY":    c[i].Key,
+			"VALUE":  value,
+			"SOURCE": c[i].Source,
+		})
+	}
+
+	return data
+}
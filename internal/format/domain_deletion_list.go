@@ -0,0 +1,64 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+)
+
+type DomainDeletionList []dnsimple.Domain
+
+func (d DomainDeletionList) FormatJSON(opts *Options) (io.Reader, error) {
+	return formatJSON(d, opts)
+}
+
+func (d DomainDeletionList) FormatYAML(opts *Options) (io.Reader, error) {
+	return formatYAML(d, opts)
+}
+
+func (d DomainDeletionList) FormatTable(_ *Options) (io.Reader, error) {
+	return formatTable(d)
+}
+
+func (d DomainDeletionList) formatJSON(opts *Options) ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+func (d DomainDeletionList) formatHeader() []string {
+	return []string{
+		"DOMAIN",
+		"STATE",
+		"EXPIRES AT",
+	}
+}
+
+func (d DomainDeletionList) formatRows() []map[string]string {
+	data := make([]map[string]string, 0, len(d))
+
+	for i := range d {
+		data = append(data, map[string]string{
+			"DOMAIN":     d[i].Name,
+			"STATE":      d[i].State,
+			"EXPIRES AT": d[i].ExpiresAt,
+		})
+	}
+
+	return data
+}
@@ -0,0 +1,73 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type Profile struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Active bool   `json:"active"`
+}
+
+type ProfileList []Profile
+
+func (p ProfileList) FormatJSON(opts *Options) (io.Reader, error) {
+	return formatJSON(p, opts)
+}
+
+func (p ProfileList) FormatYAML(opts *Options) (io.Reader, error) {
+	return formatYAML(p, opts)
+}
+
+func (p ProfileList) FormatTable(_ *Options) (io.Reader, error) {
+	return formatTable(p)
+}
+
+func (p ProfileList) formatJSON(opts *Options) ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+func (p ProfileList) formatHeader() []string {
+	return []string{
+		"ACTIVE",
+		"NAME",
+		"PATH",
+	}
+}
+
+func (p ProfileList) formatRows() []map[string]string {
+	data := make([]map[string]string, 0, len(p))
+
+	for i := range p {
+		active := ""
+		if p[i].Active {
+			active = "*"
+		}
+
+		data = append(data, map[string]string{
+			"ACTIVE": active,
+			"NAME":   p[i].Name,
+			"PATH":   p[i].Path,
+		})
+	}
+
+	return data
+}
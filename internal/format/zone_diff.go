@@ -0,0 +1,68 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/snapshot"
+)
+
+type ZoneDiff snapshot.Diff
+
+func (z ZoneDiff) FormatText(_ *Options) (io.Reader, error) {
+	buf := new(bytes.Buffer)
+
+	buf.WriteString(fmt.Sprintf("--- %s\n", z.From))
+	buf.WriteString(fmt.Sprintf("+++ %s\n", z.To))
+
+	for _, c := range z.Changes {
+		if c.Old != nil {
+			buf.WriteString("-" + recordLine(*c.Old) + "\n")
+		}
+
+		if c.New != nil {
+			buf.WriteString("+" + recordLine(*c.New) + "\n")
+		}
+	}
+
+	return buf, nil
+}
+
+func (z ZoneDiff) FormatJSON(opts *Options) (io.Reader, error) {
+	return formatJSON(z, opts)
+}
+
+func (z ZoneDiff) FormatYAML(opts *Options) (io.Reader, error) {
+	return formatYAML(z, opts)
+}
+
+func (z ZoneDiff) formatJSON(opts *Options) ([]byte, error) {
+	return json.MarshalIndent(z, "", "  ")
+}
+
+func recordLine(r dnsimple.ZoneRecord) string {
+	if r.Priority != 0 {
+		return fmt.Sprintf("%s\t%d\tIN\t%s\t%d\t%s", recordName(r.Name), r.TTL, r.Type, r.Priority, r.Content)
+	}
+
+	return fmt.Sprintf("%s\t%d\tIN\t%s\t%s", recordName(r.Name), r.TTL, r.Type, r.Content)
+}
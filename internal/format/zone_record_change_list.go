@@ -0,0 +1,95 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type ZoneRecordChange struct {
+	Zone     string `json:"zone"`
+	RecordID int64  `json:"record_id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Status   string `json:"status,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type ZoneRecordChangeList []ZoneRecordChange
+
+func (z ZoneRecordChangeList) FormatText(_ *Options) (io.Reader, error) {
+	buf := new(bytes.Buffer)
+
+	for _, c := range z {
+		buf.WriteString(fmt.Sprintf("%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n", c.Zone, c.RecordID, recordName(c.Name), c.Type, c.From, c.To, c.Status, c.Error))
+	}
+
+	return buf, nil
+}
+
+func (z ZoneRecordChangeList) FormatJSON(opts *Options) (io.Reader, error) {
+	return formatJSON(z, opts)
+}
+
+func (z ZoneRecordChangeList) FormatYAML(opts *Options) (io.Reader, error) {
+	return formatYAML(z, opts)
+}
+
+func (z ZoneRecordChangeList) FormatTable(_ *Options) (io.Reader, error) {
+	return formatTable(z)
+}
+
+func (z ZoneRecordChangeList) formatJSON(opts *Options) ([]byte, error) {
+	return json.MarshalIndent(z, "", "  ")
+}
+
+func (z ZoneRecordChangeList) formatHeader() []string {
+	return []string{
+		"ZONE",
+		"ID",
+		"NAME",
+		"TYPE",
+		"FROM",
+		"TO",
+		"STATUS",
+		"ERROR",
+	}
+}
+
+func (z ZoneRecordChangeList) formatRows() []map[string]string {
+	data := make([]map[string]string, 0, len(z))
+
+	for i := range z {
+		data = append(data, map[string]string{
+			"ZONE":   z[i].Zone,
+			"ID":     fmt.Sprintf("%d", z[i].RecordID),
+			"NAME":   recordName(z[i].Name),
+			"TYPE":   z[i].Type,
+			"FROM":   z[i].From,
+			"TO":     z[i].To,
+			"STATUS": z[i].Status,
+			"ERROR":  z[i].Error,
+		})
+	}
+
+	return data
+}
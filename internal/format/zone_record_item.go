@@ -0,0 +1,92 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+)
+
+type ZoneRecordItem dnsimple.ZoneRecordResponse
+
+func (z ZoneRecordItem) FormatText(opts *Options) (io.Reader, error) {
+	keys := []string{
+		"id",
+		"zone_id",
+		"name",
+		"type",
+		"content",
+		"ttl",
+		"priority",
+		"regions",
+		"system_record",
+		"created_at",
+		"updated_at",
+	}
+
+	values := map[string]interface{}{
+		"id":            z.Data.ID,
+		"zone_id":       z.Data.ZoneID,
+		"name":          recordName(z.Data.Name),
+		"type":          z.Data.Type,
+		"content":       z.Data.Content,
+		"ttl":           z.Data.TTL,
+		"priority":      z.Data.Priority,
+		"regions":       strings.Join(z.Data.Regions, ", "),
+		"system_record": z.Data.SystemRecord,
+		"created_at":    z.Data.CreatedAt,
+		"updated_at":    z.Data.UpdatedAt,
+	}
+
+	titles := map[string]string{
+		"id":            "ID",
+		"zone_id":       "Zone ID",
+		"name":          "Name",
+		"type":          "Type",
+		"content":       "Content",
+		"ttl":           "TTL",
+		"priority":      "Priority",
+		"regions":       "Regions",
+		"system_record": "System record",
+		"created_at":    "Created at",
+		"updated_at":    "Updated at",
+	}
+
+	buf := new(bytes.Buffer)
+	for _, v := range keys {
+		buf.WriteString(fmt.Sprintf("%-20s%v\n", titles[v]+":", values[v]))
+	}
+
+	return buf, nil
+}
+
+func (z ZoneRecordItem) FormatJSON(opts *Options) (io.Reader, error) {
+	return formatJSON(z, opts)
+}
+
+func (z ZoneRecordItem) FormatYAML(opts *Options) (io.Reader, error) {
+	return formatYAML(z, opts)
+}
+
+func (z ZoneRecordItem) formatJSON(opts *Options) ([]byte, error) {
+	return json.MarshalIndent(z.Data, "", "  ")
+}
@@ -0,0 +1,113 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+)
+
+type ZoneRecordMatch struct {
+	AccountID string              `json:"account_id,omitempty"`
+	Zone      string              `json:"zone"`
+	Record    dnsimple.ZoneRecord `json:"record"`
+}
+
+type ZoneRecordMatchList []ZoneRecordMatch
+
+func (z ZoneRecordMatchList) FormatText(_ *Options) (io.Reader, error) {
+	buf := new(bytes.Buffer)
+
+	for _, m := range z {
+		if m.AccountID != "" {
+			buf.WriteString(m.AccountID + "\t")
+		}
+
+		buf.WriteString(fmt.Sprintf("%s\t%d\t%s\t%s\t%s\n", m.Zone, m.Record.ID, recordName(m.Record.Name), m.Record.Type, m.Record.Content))
+	}
+
+	return buf, nil
+}
+
+func (z ZoneRecordMatchList) FormatJSON(opts *Options) (io.Reader, error) {
+	return formatJSON(z, opts)
+}
+
+func (z ZoneRecordMatchList) FormatYAML(opts *Options) (io.Reader, error) {
+	return formatYAML(z, opts)
+}
+
+func (z ZoneRecordMatchList) FormatTable(_ *Options) (io.Reader, error) {
+	return formatTable(z)
+}
+
+func (z ZoneRecordMatchList) formatJSON(opts *Options) ([]byte, error) {
+	return json.MarshalIndent(z, "", "  ")
+}
+
+func (z ZoneRecordMatchList) formatHeader() []string {
+	header := []string{
+		"ZONE",
+		"ID",
+		"NAME",
+		"TYPE",
+		"CONTENT",
+		"TTL",
+		"PRIORITY",
+	}
+
+	// The account is only known, and only relevant, when searching several
+	// accounts at once.
+	for i := range z {
+		if z[i].AccountID != "" {
+			return append([]string{"ACCOUNT ID"}, header...)
+		}
+	}
+
+	return header
+}
+
+func (z ZoneRecordMatchList) formatRows() []map[string]string {
+	data := make([]map[string]string, 0, len(z))
+
+	for i := range z {
+		data = append(data, map[string]string{
+			"ACCOUNT ID": z[i].AccountID,
+			"ZONE":       z[i].Zone,
+			"ID":         fmt.Sprintf("%d", z[i].Record.ID),
+			"NAME":       recordName(z[i].Record.Name),
+			"TYPE":       z[i].Record.Type,
+			"CONTENT":    z[i].Record.Content,
+			"TTL":        fmt.Sprintf("%d", z[i].Record.TTL),
+			"PRIORITY":   fmt.Sprintf("%d", z[i].Record.Priority),
+		})
+	}
+
+	return data
+}
+
+func recordName(name string) string {
+	if name == "" {
+		return "@"
+	}
+
+	return name
+}
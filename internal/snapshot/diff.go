@@ -0,0 +1,160 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+)
+
+const (
+	ActionAdd    = "add"
+	ActionRemove = "remove"
+	ActionChange = "change"
+)
+
+type Diff struct {
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Changes []Change `json:"changes"`
+}
+
+type Change struct {
+	Action string               `json:"action"`
+	Old    *dnsimple.ZoneRecord `json:"old,omitempty"`
+	New    *dnsimple.ZoneRecord `json:"new,omitempty"`
+}
+
+// Compare returns the changes that turn the from records into the to
+// records. System records are ignored. Records are identified by name, type
+// and content; when a name and type pair has exactly one unmatched record on
+// each side they are reported as a single change.
+func Compare(from, to []dnsimple.ZoneRecord) []Change {
+	var (
+		changes   []Change
+		remaining = make(map[string][]dnsimple.ZoneRecord)
+		added     = make(map[string][]dnsimple.ZoneRecord)
+		removed   = make(map[string][]dnsimple.ZoneRecord)
+	)
+
+	for _, r := range from {
+		if r.SystemRecord {
+			continue
+		}
+
+		remaining[recordKey(r)] = append(remaining[recordKey(r)], r)
+	}
+
+	for _, r := range to {
+		if r.SystemRecord {
+			continue
+		}
+
+		key := recordKey(r)
+
+		if len(remaining[key]) == 0 {
+			added[rrsetKey(r)] = append(added[rrsetKey(r)], r)
+
+			continue
+		}
+
+		old := remaining[key][0]
+		remaining[key] = remaining[key][1:]
+
+		if !sameAttributes(old, r) {
+			changes = append(changes, Change{Action: ActionChange, Old: recordP(old), New: recordP(r)})
+		}
+	}
+
+	for _, records := range remaining {
+		for _, r := range records {
+			removed[rrsetKey(r)] = append(removed[rrsetKey(r)], r)
+		}
+	}
+
+	for key, records := range added {
+		if len(records) == 1 && len(removed[key]) == 1 {
+			changes = append(changes, Change{Action: ActionChange, Old: recordP(removed[key][0]), New: recordP(records[0])})
+			delete(removed, key)
+
+			continue
+		}
+
+		for _, r := range records {
+			changes = append(changes, Change{Action: ActionAdd, New: recordP(r)})
+		}
+	}
+
+	for _, records := range removed {
+		for _, r := range records {
+			changes = append(changes, Change{Action: ActionRemove, Old: recordP(r)})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		a, b := changes[i].Record(), changes[j].Record()
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+
+		return a.Content < b.Content
+	})
+
+	return changes
+}
+
+// Record returns the record the change is about, preferring its new state.
+func (c Change) Record() dnsimple.ZoneRecord {
+	if c.New != nil {
+		return *c.New
+	}
+
+	return *c.Old
+}
+
+func recordKey(r dnsimple.ZoneRecord) string {
+	return fmt.Sprintf("%s\x00%s\x00%s", r.Name, strings.ToUpper(r.Type), r.Content)
+}
+
+func rrsetKey(r dnsimple.ZoneRecord) string {
+	return fmt.Sprintf("%s\x00%s", r.Name, strings.ToUpper(r.Type))
+}
+
+func sameAttributes(a, b dnsimple.ZoneRecord) bool {
+	if a.TTL != b.TTL || a.Priority != b.Priority || len(a.Regions) != len(b.Regions) {
+		return false
+	}
+
+	for i := range a.Regions {
+		if a.Regions[i] != b.Regions[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func recordP(r dnsimple.ZoneRecord) *dnsimple.ZoneRecord {
+	return &r
+}
@@ -0,0 +1,139 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/snapshot"
+)
+
+func TestCompare(t *testing.T) {
+	soa := dnsimple.ZoneRecord{Type: "SOA", Content: "ns1.dnsimple.com", TTL: 3600, SystemRecord: true}
+
+	tests := []struct {
+		name string
+		from []dnsimple.ZoneRecord
+		to   []dnsimple.ZoneRecord
+		want []string
+	}{
+		{
+			name: "identical",
+			from: []dnsimple.ZoneRecord{a("www", "192.0.2.1", 3600)},
+			to:   []dnsimple.ZoneRecord{a("www", "192.0.2.1", 3600)},
+		},
+		{
+			name: "added",
+			to:   []dnsimple.ZoneRecord{a("www", "192.0.2.1", 3600)},
+			want: []string{"add www A 192.0.2.1/3600"},
+		},
+		{
+			name: "removed",
+			from: []dnsimple.ZoneRecord{a("www", "192.0.2.1", 3600)},
+			want: []string{"remove www A 192.0.2.1/3600"},
+		},
+		{
+			name: "changed content",
+			from: []dnsimple.ZoneRecord{a("www", "192.0.2.1", 3600)},
+			to:   []dnsimple.ZoneRecord{a("www", "192.0.2.2", 3600)},
+			want: []string{"change www A 192.0.2.1/3600 -> 192.0.2.2/3600"},
+		},
+		{
+			name: "changed ttl only",
+			from: []dnsimple.ZoneRecord{a("www", "192.0.2.1", 3600)},
+			to:   []dnsimple.ZoneRecord{a("www", "192.0.2.1", 300)},
+			want: []string{"change www A 192.0.2.1/3600 -> 192.0.2.1/300"},
+		},
+		{
+			name: "multi-value rrset with one value changed",
+			from: []dnsimple.ZoneRecord{a("", "192.0.2.1", 3600), a("", "192.0.2.2", 3600)},
+			to:   []dnsimple.ZoneRecord{a("", "192.0.2.1", 3600), a("", "192.0.2.3", 3600)},
+			want: []string{"change @ A 192.0.2.2/3600 -> 192.0.2.3/3600"},
+		},
+		{
+			name: "multi-value rrset with several values changed",
+			from: []dnsimple.ZoneRecord{a("", "192.0.2.1", 3600), a("", "192.0.2.2", 3600)},
+			to:   []dnsimple.ZoneRecord{a("", "192.0.2.3", 3600), a("", "192.0.2.4", 3600)},
+			want: []string{
+				"remove @ A 192.0.2.1/3600",
+				"remove @ A 192.0.2.2/3600",
+				"add @ A 192.0.2.3/3600",
+				"add @ A 192.0.2.4/3600",
+			},
+		},
+		{
+			name: "same content under another name",
+			from: []dnsimple.ZoneRecord{a("www", "192.0.2.1", 3600)},
+			to:   []dnsimple.ZoneRecord{a("api", "192.0.2.1", 3600)},
+			want: []string{"add api A 192.0.2.1/3600", "remove www A 192.0.2.1/3600"},
+		},
+		{
+			name: "type is case insensitive",
+			from: []dnsimple.ZoneRecord{{Name: "www", Type: "a", Content: "192.0.2.1", TTL: 3600}},
+			to:   []dnsimple.ZoneRecord{a("www", "192.0.2.1", 3600)},
+		},
+		{
+			name: "system records are ignored",
+			from: []dnsimple.ZoneRecord{soa},
+			to:   []dnsimple.ZoneRecord{a("www", "192.0.2.1", 3600)},
+			want: []string{"add www A 192.0.2.1/3600"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+
+			for _, c := range snapshot.Compare(tt.from, tt.to) {
+				got = append(got, describe(c))
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compare() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func a(name, content string, ttl int) dnsimple.ZoneRecord {
+	return dnsimple.ZoneRecord{Name: name, Type: "A", Content: content, TTL: ttl}
+}
+
+func describe(c snapshot.Change) string {
+	r := c.Record()
+
+	name := r.Name
+	if name == "" {
+		name = "@"
+	}
+
+	value := func(r *dnsimple.ZoneRecord) string {
+		return fmt.Sprintf("%s/%d", r.Content, r.TTL)
+	}
+
+	switch c.Action {
+	case snapshot.ActionAdd:
+		return fmt.Sprintf("add %s %s %s", name, r.Type, value(c.New))
+	case snapshot.ActionRemove:
+		return fmt.Sprintf("remove %s %s %s", name, r.Type, value(c.Old))
+	default:
+		return fmt.Sprintf("%s %s %s %s -> %s", c.Action, name, r.Type, value(c.Old), value(c.New))
+	}
+}
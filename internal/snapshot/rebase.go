@@ -0,0 +1,96 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"strings"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+)
+
+// hostnameTypes lists the record types whose content ends with a hostname.
+var hostnameTypes = map[string]struct{}{
+	"ALIAS": {},
+	"CNAME": {},
+	"MX":    {},
+	"NS":    {},
+	"PTR":   {},
+	"SRV":   {},
+}
+
+// Rebase returns a copy of records in which names and hostnames that
+// reference the from apex are rewritten to reference the to apex instead.
+// Record names are made relative to the apex.
+func Rebase(records []dnsimple.ZoneRecord, from, to string) []dnsimple.ZoneRecord {
+	from = strings.TrimSuffix(strings.ToLower(from), ".")
+	to = strings.TrimSuffix(strings.ToLower(to), ".")
+
+	rebased := make([]dnsimple.ZoneRecord, 0, len(records))
+
+	for _, r := range records {
+		r.Name = relativeName(r.Name, from)
+
+		if _, ok := hostnameTypes[strings.ToUpper(r.Type)]; ok {
+			fields := strings.Fields(r.Content)
+			if len(fields) != 0 {
+				last := len(fields) - 1
+				fields[last] = rebaseHostname(fields[last], from, to)
+				r.Content = strings.Join(fields, " ")
+			}
+		}
+
+		rebased = append(rebased, r)
+	}
+
+	return rebased
+}
+
+func relativeName(name, apex string) string {
+	n := strings.TrimSuffix(strings.ToLower(name), ".")
+
+	if n == apex {
+		return ""
+	}
+
+	if strings.HasSuffix(n, "."+apex) {
+		return name[:len(n)-len(apex)-1]
+	}
+
+	return name
+}
+
+func rebaseHostname(host, from, to string) string {
+	fqdn := strings.HasSuffix(host, ".")
+	h := strings.TrimSuffix(host, ".")
+
+	var rebased string
+
+	switch {
+	case strings.EqualFold(h, from):
+		rebased = to
+	case strings.HasSuffix(strings.ToLower(h), "."+from):
+		rebased = h[:len(h)-len(from)] + to
+	default:
+		return host
+	}
+
+	if fqdn {
+		rebased += "."
+	}
+
+	return rebased
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+)
+
+const (
+	Version    = 1
+	timeLayout = "20060102T150405.000Z"
+)
+
+type Snapshot struct {
+	Version   int                   `json:"version"`
+	CreatedAt time.Time             `json:"created_at"`
+	Zone      string                `json:"zone"`
+	Domain    *dnsimple.Domain      `json:"domain,omitempty"`
+	Records   []dnsimple.ZoneRecord `json:"records"`
+}
+
+func New(zone string, domain *dnsimple.Domain, records []dnsimple.ZoneRecord) *Snapshot {
+	return &Snapshot{
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+		Zone:      zone,
+		Domain:    domain,
+		Records:   records,
+	}
+}
+
+func Read(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", path, err)
+	}
+
+	if s.Version != Version {
+		return nil, fmt.Errorf("unsupported snapshot version %d", s.Version)
+	}
+
+	if s.Zone == "" {
+		return nil, errors.New("snapshot has no zone")
+	}
+
+	return &s, nil
+}
+
+// FileName returns the timestamped name under which the snapshot is stored.
+func (s *Snapshot) FileName() string {
+	return fmt.Sprintf("%s-%s.json", s.Zone, s.CreatedAt.Format(timeLayout))
+}
+
+// WriteDir stores the snapshot in dir, creating it if needed, and returns
+// the path of the written file. An existing file is never overwritten: a
+// snapshot named like one already in dir gets a numbered suffix.
+func (s *Snapshot) WriteDir(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	name := strings.TrimSuffix(s.FileName(), ".json")
+
+	for n := 1; ; n++ {
+		path := filepath.Join(dir, s.FileName())
+		if n > 1 {
+			path = filepath.Join(dir, fmt.Sprintf("%s-%d.json", name, n))
+		}
+
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		_, err = f.Write(data)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		return path, nil
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/snapshot"
+)
+
+func TestWriteDirKeepsExistingSnapshots(t *testing.T) {
+	dir := t.TempDir()
+
+	s := snapshot.New("example.com", nil, []dnsimple.ZoneRecord{a("www", "192.0.2.1", 3600)})
+	s.CreatedAt = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	paths := make(map[string]bool)
+
+	for i := 0; i < 3; i++ {
+		path, err := s.WriteDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		paths[path] = true
+	}
+
+	if len(paths) != 3 {
+		t.Fatalf("wrote %d distinct file(s), want 3", len(paths))
+	}
+
+	for path := range paths {
+		got, err := snapshot.Read(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(got.Records, s.Records) {
+			t.Errorf("%s holds %v, want %v", path, got.Records, s.Records)
+		}
+	}
+}
+
+func TestFileName(t *testing.T) {
+	s := snapshot.New("example.com", nil, nil)
+	s.CreatedAt = time.Date(2023, 1, 1, 0, 0, 0, 250*int(time.Millisecond), time.UTC)
+
+	if got, want := s.FileName(), "example.com-20230101T000000.250Z.json"; got != want {
+		t.Errorf("FileName() = %s, want %s", got, want)
+	}
+}
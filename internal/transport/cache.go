@@ -0,0 +1,260 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transport
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const cacheEntryExt = ".json"
+
+// Cache answers GET requests from responses stored on disk for up to TTL and
+// stores the successful responses it fetches. Entries live in a directory
+// tree mirroring the request paths under Dir, so that successful requests
+// with other methods can drop the entries of the resource they change: the
+// entries below the domain or zone they touch, with its domain and zone
+// counterparts, and the collections the resource belongs to.
+//
+// With a zero TTL nothing is read or stored, but mutations still invalidate
+// the entries stored by other commands.
+type Cache struct {
+	Base http.RoundTripper
+	Dir  string
+	TTL  time.Duration
+
+	now func() time.Time
+}
+
+type cacheEntry struct {
+	StoredAt time.Time   `json:"stored_at"`
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+}
+
+func NewCache(base http.RoundTripper, dir string, ttl time.Duration) *Cache {
+	return &Cache{
+		Base: base,
+		Dir:  dir,
+		TTL:  ttl,
+		now:  time.Now,
+	}
+}
+
+func (t *Cache) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		resp, err := t.base().RoundTrip(req)
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			t.invalidate(req.URL)
+		}
+
+		return resp, err
+	}
+
+	if t.TTL <= 0 {
+		return t.base().RoundTrip(req)
+	}
+
+	path := t.entryPath(req)
+
+	if resp := t.load(path, req); resp != nil {
+		return resp, nil
+	}
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Failing to write the cache must not fail the request.
+	_ = t.store(path, cacheEntry{
+		StoredAt: t.now(),
+		Status:   resp.StatusCode,
+		Header:   resp.Header,
+		Body:     body,
+	})
+
+	return resp, nil
+}
+
+func (t *Cache) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+
+	return http.DefaultTransport
+}
+
+func (t *Cache) load(path string, req *http.Request) *http.Response {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || t.now().Sub(entry.StoredAt) > t.TTL {
+		return nil
+	}
+
+	return &http.Response{
+		Status:        http.StatusText(entry.Status),
+		StatusCode:    entry.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        entry.Header,
+		Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}
+}
+
+func (t *Cache) store(path string, entry cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that concurrent commands never read
+	// a partial entry.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".entry-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// entryPath returns the file of the entry of req: one file per query string
+// and credentials in the directory of its path, so that a response is only
+// served to requests made with the token that fetched it.
+func (t *Cache) entryPath(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Header.Get("Authorization") + "\n" + req.URL.Query().Encode()))
+
+	return filepath.Join(t.resourceDir(req.URL.Host, segments(req.URL.Path)...), hex.EncodeToString(sum[:8])+cacheEntryExt)
+}
+
+func (t *Cache) resourceDir(host string, segments ...string) string {
+	elems := []string{t.Dir, escapeSegment(host)}
+
+	for _, segment := range segments {
+		elems = append(elems, escapeSegment(segment))
+	}
+
+	return filepath.Join(elems...)
+}
+
+// invalidate drops the entries that a change to the resource at u can make
+// stale.
+func (t *Cache) invalidate(u *url.URL) {
+	parts := segments(u.Path)
+
+	// Paths look like /v2/<account>/<collection>/<name>/...
+	if len(parts) < 3 {
+		_ = os.RemoveAll(t.resourceDir(u.Host, parts...))
+
+		return
+	}
+
+	version, account, collection := parts[0], parts[1], parts[2]
+
+	// Domains and zones are two views of the same resource.
+	collections := []string{collection}
+	if collection == "domains" || collection == "zones" {
+		collections = []string{"domains", "zones"}
+	}
+
+	for _, c := range collections {
+		// The listings of the collection include the resource.
+		removeEntries(t.resourceDir(u.Host, version, account, c))
+
+		if len(parts) >= 4 {
+			_ = os.RemoveAll(t.resourceDir(u.Host, version, account, c, parts[3]))
+		}
+	}
+}
+
+// removeEntries removes the entries stored directly in dir, leaving those of
+// the resources below it.
+func removeEntries(dir string) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), cacheEntryExt) {
+			_ = os.Remove(filepath.Join(dir, file.Name()))
+		}
+	}
+}
+
+func segments(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, "/")
+}
+
+// escapeSegment turns a path segment into a safe file name.
+func escapeSegment(segment string) string {
+	segment = strings.ReplaceAll(url.PathEscape(strings.ToLower(segment)), ":", "%3A")
+
+	switch segment {
+	case ".", "..":
+		return "%2E" + segment[1:]
+	}
+
+	return segment
+}
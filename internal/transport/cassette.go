@@ -0,0 +1,255 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+const cassetteVersion = 1
+
+// Cassette holds the API interactions of a command, in the order they were
+// made. Credentials are redacted before they are recorded.
+type Cassette struct {
+	Version      int           `yaml:"version"`
+	Interactions []Interaction `yaml:"interactions"`
+}
+
+type Interaction struct {
+	Request  RecordedRequest  `yaml:"request"`
+	Response RecordedResponse `yaml:"response"`
+}
+
+type RecordedRequest struct {
+	Method  string              `yaml:"method"`
+	URL     string              `yaml:"url"`
+	Headers map[string][]string `yaml:"headers,omitempty"`
+	Body    string              `yaml:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	Status  int                 `yaml:"status"`
+	Headers map[string][]string `yaml:"headers,omitempty"`
+	Body    string              `yaml:"body,omitempty"`
+}
+
+// ReadCassette reads the cassette at path.
+func ReadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cassette
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+
+	if c.Version != cassetteVersion {
+		return nil, fmt.Errorf("unsupported cassette version %d", c.Version)
+	}
+
+	return &c, nil
+}
+
+// Recorder sends requests with Base and records them and their responses in
+// the cassette at Path, which is rewritten after every interaction so that
+// interrupted commands leave a usable cassette behind.
+type Recorder struct {
+	Base http.RoundTripper
+	Path string
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+func NewRecorder(base http.RoundTripper, path string) *Recorder {
+	return &Recorder{
+		Base:     base,
+		Path:     path,
+		cassette: Cassette{Version: cassetteVersion},
+	}
+}
+
+func (t *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.cassette.Interactions = append(t.cassette.Interactions, Interaction{
+		Request: RecordedRequest{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: redactHeaders(req.Header),
+			Body:    string(redactBody(reqBody)),
+		},
+		Response: RecordedResponse{
+			Status:  resp.StatusCode,
+			Headers: redactHeaders(resp.Header),
+			Body:    string(redactBody(respBody)),
+		},
+	})
+
+	buf := new(bytes.Buffer)
+
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(&t.cassette); err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(t.Path, buf.Bytes(), 0o600); err != nil {
+		return nil, fmt.Errorf("writing cassette: %w", err)
+	}
+
+	return resp, nil
+}
+
+// Replayer answers requests with the interactions of the cassette at Path
+// instead of sending them. A request is answered by the first unused
+// interaction with the same method, path, query and body, so the scheme and
+// host of the recording do not matter, nor does the order of concurrent
+// requests.
+type Replayer struct {
+	Path string
+
+	once     sync.Once
+	err      error
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+func NewReplayer(path string) *Replayer {
+	return &Replayer{Path: path}
+}
+
+func (t *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.once.Do(func() {
+		t.cassette, t.err = ReadCassette(t.Path)
+		if t.err == nil {
+			t.used = make([]bool, len(t.cassette.Interactions))
+		}
+	})
+
+	if t.err != nil {
+		return nil, t.err
+	}
+
+	body, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	body = redactBody(body)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, interaction := range t.cassette.Interactions {
+		if t.used[i] || !matches(interaction.Request, req, body) {
+			continue
+		}
+
+		t.used[i] = true
+
+		resp := interaction.Response
+		header := http.Header{}
+
+		for key, values := range resp.Headers {
+			header[http.CanonicalHeaderKey(key)] = values
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", resp.Status, http.StatusText(resp.Status)),
+			StatusCode:    resp.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(resp.Body)),
+			ContentLength: int64(len(resp.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("cassette %s has no interaction for %s %s", t.Path, req.Method, req.URL.RequestURI())
+}
+
+func matches(recorded RecordedRequest, req *http.Request, body []byte) bool {
+	if recorded.Method != req.Method || recorded.Body != string(body) {
+		return false
+	}
+
+	u, err := url.Parse(recorded.URL)
+
+	return err == nil && u.RequestURI() == req.URL.RequestURI()
+}
+
+func redactHeaders(header http.Header) map[string][]string {
+	if len(header) == 0 {
+		return nil
+	}
+
+	redactedHeader := make(map[string][]string, len(header))
+
+	for key, values := range header {
+		if sensitiveHeaders[http.CanonicalHeaderKey(key)] {
+			values = []string{redacted}
+		}
+
+		redactedHeader[key] = values
+	}
+
+	return redactedHeader
+}
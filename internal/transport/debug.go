@@ -0,0 +1,193 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	sensitiveHeaders = map[string]bool{
+		"Authorization": true,
+		"Cookie":        true,
+		"Set-Cookie":    true,
+	}
+
+	// sensitiveFields matches the JSON fields carrying credentials, such as
+	// the ones of the OAuth token exchange.
+	sensitiveFields = regexp.MustCompile(`("(?:access_token|client_secret|code)"\s*:\s*)"[^"]*"`)
+)
+
+// Debug logs every request and its response to Out: method, URL, status,
+// latency and rate limit headers, and with Bodies also the headers and
+// bodies. Credentials are always redacted.
+type Debug struct {
+	Base   http.RoundTripper
+	Out    io.Writer
+	Bodies bool
+
+	mu sync.Mutex
+}
+
+func NewDebug(base http.RoundTripper, out io.Writer, bodies bool) *Debug {
+	return &Debug{
+		Base:   base,
+		Out:    out,
+		Bodies: bodies,
+	}
+}
+
+func (t *Debug) RoundTrip(req *http.Request) (*http.Response, error) {
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "> %s %s\n", req.Method, req.URL)
+
+	if t.Bodies {
+		writeHeaders(buf, ">", req.Header)
+
+		body, err := requestBody(req)
+		if err != nil {
+			return nil, err
+		}
+
+		writeBody(buf, ">", body)
+	}
+
+	start := time.Now()
+	resp, err := t.base().RoundTrip(req)
+	latency := time.Since(start).Round(time.Millisecond)
+
+	if err != nil {
+		fmt.Fprintf(buf, "< error after %s: %v\n", latency, err)
+		t.write(buf)
+
+		return nil, err
+	}
+
+	fmt.Fprintf(buf, "< %s (%s)\n", resp.Status, latency)
+
+	if t.Bodies {
+		writeHeaders(buf, "<", resp.Header)
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		if err != nil {
+			t.write(buf)
+
+			return nil, err
+		}
+
+		writeBody(buf, "<", body)
+	} else {
+		for _, key := range []string{"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After"} {
+			if value := resp.Header.Get(key); value != "" {
+				fmt.Fprintf(buf, "< %s: %s\n", key, value)
+			}
+		}
+	}
+
+	t.write(buf)
+
+	return resp, nil
+}
+
+func (t *Debug) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+
+	return http.DefaultTransport
+}
+
+// write prints a whole exchange at once so that concurrent requests do not
+// interleave.
+func (t *Debug) write(buf *bytes.Buffer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, _ = buf.WriteTo(t.Out)
+}
+
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+
+		return io.ReadAll(body)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, err
+}
+
+func writeHeaders(buf *bytes.Buffer, prefix string, header http.Header) {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := strings.Join(header[key], ", ")
+		if sensitiveHeaders[http.CanonicalHeaderKey(key)] {
+			value = redacted
+		}
+
+		fmt.Fprintf(buf, "%s %s: %s\n", prefix, key, value)
+	}
+}
+
+func writeBody(buf *bytes.Buffer, prefix string, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+
+	body = redactBody(body)
+
+	buf.WriteString(prefix + "\n")
+
+	for _, line := range strings.Split(strings.TrimRight(string(body), "\n"), "\n") {
+		fmt.Fprintf(buf, "%s %s\n", prefix, line)
+	}
+}
+
+// redactBody replaces the values of the credential fields of a JSON body.
+func redactBody(body []byte) []byte {
+	return sensitiveFields.ReplaceAll(body, []byte(`$1"`+redacted+`"`))
+}
@@ -0,0 +1,115 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transport_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/edsonmichaque/dnsimple-cli/internal/transport"
+)
+
+func TestDebugRedactsCredentials(t *testing.T) {
+	secrets := []string{"bearer-secret", "cookie-secret", "set-cookie-secret", "client-secret-value", "authorization-code", "issued-token"}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "set-cookie-secret"})
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"access_token":"issued-token","token_type":"bearer","account_id":1010}`)
+	}))
+	defer server.Close()
+
+	body := `{"grant_type":"authorization_code","client_id":"cli","client_secret":"client-secret-value","code":"authorization-code"}`
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/v2/oauth/access_token", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Authorization", "Bearer bearer-secret")
+	req.Header.Set("Cookie", "session=cookie-secret")
+
+	out := new(bytes.Buffer)
+
+	resp, err := transport.NewDebug(http.DefaultTransport, out, true).RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	got, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(got), "issued-token") {
+		t.Errorf("the response body was altered: %s", got)
+	}
+
+	dump := out.String()
+
+	for _, secret := range secrets {
+		if strings.Contains(dump, secret) {
+			t.Errorf("the debug output leaks %q:\n%s", secret, dump)
+		}
+	}
+
+	for _, want := range []string{"Authorization: [REDACTED]", "Cookie: [REDACTED]", "Set-Cookie: [REDACTED]", `"grant_type":"authorization_code"`, `"token_type":"bearer"`} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("the debug output does not contain %q:\n%s", want, dump)
+		}
+	}
+}
+
+func TestDebugWithoutBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "2399")
+		_, _ = io.WriteString(w, `{"data":{}}`)
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/v2/whoami", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Authorization", "Bearer bearer-secret")
+
+	out := new(bytes.Buffer)
+
+	resp, err := transport.NewDebug(http.DefaultTransport, out, false).RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	dump := out.String()
+
+	if strings.Contains(dump, "bearer-secret") || strings.Contains(dump, "Authorization") {
+		t.Errorf("headers are logged without --debug-http-body:\n%s", dump)
+	}
+
+	for _, want := range []string{"> GET " + server.URL + "/v2/whoami", "< 200 OK", "< X-RateLimit-Remaining: 2399"} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("the debug output does not contain %q:\n%s", want, dump)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package transport provides the http.RoundTripper layers used by the API
+// client.
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMaxRetries = 3
+	defaultMinBackoff = 500 * time.Millisecond
+	defaultMaxBackoff = 30 * time.Second
+
+	headerRateLimitRemaining = "X-RateLimit-Remaining"
+	headerRateLimitReset     = "X-RateLimit-Reset"
+	headerRetryAfter         = "Retry-After"
+
+	// rateLimitReserve is the number of requests kept in reserve: once the
+	// remaining requests drop to it, requests wait for the window to reset.
+	rateLimitReserve = 1
+)
+
+// Retry retries requests that failed with a network error or a 5xx status
+// when the request is idempotent, and any request rejected with 429. Retries
+// are spaced with jittered exponential backoff. It also tracks the rate limit
+// headers of the responses and holds requests back until the rate limit
+// window resets when it is about to be exhausted.
+type Retry struct {
+	Base       http.RoundTripper
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	mu        sync.Mutex
+	remaining int
+	reset     time.Time
+}
+
+func NewRetry(base http.RoundTripper, maxRetries int) *Retry {
+	return &Retry{
+		Base:       base,
+		MaxRetries: maxRetries,
+		MinBackoff: defaultMinBackoff,
+		MaxBackoff: defaultMaxBackoff,
+		remaining:  -1,
+	}
+}
+
+func (t *Retry) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := t.waitForRateLimit(req.Context()); err != nil {
+			return nil, err
+		}
+
+		// A RoundTripper must not modify the request, so every retry sends a
+		// copy of it with a fresh body.
+		attemptReq := req
+		if attempt > 0 {
+			attemptReq = req.Clone(req.Context())
+
+			if req.Body != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+
+				attemptReq.Body = body
+			}
+		}
+
+		resp, err := t.base().RoundTrip(attemptReq)
+		if resp != nil {
+			t.observeRateLimit(resp)
+		}
+
+		if attempt >= t.MaxRetries || !t.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt)
+		if resp != nil {
+			if after := retryAfter(resp); after > wait {
+				wait = after
+			}
+
+			// The body must be consumed for the connection to be reused.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *Retry) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+
+	return http.DefaultTransport
+}
+
+func (t *Retry) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Body != nil && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		return isIdempotent(req.Method) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	return resp.StatusCode >= http.StatusInternalServerError && isIdempotent(req.Method)
+}
+
+// backoff returns the wait before retry number attempt+1: an exponentially
+// growing delay with up to half of it randomized.
+func (t *Retry) backoff(attempt int) time.Duration {
+	d := t.MinBackoff << attempt
+	if d <= 0 || d > t.MaxBackoff {
+		d = t.MaxBackoff
+	}
+
+	half := int64(d / 2)
+	if half <= 0 {
+		return d
+	}
+
+	// #nosec G404 -- jitter does not need a secure source
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+func (t *Retry) observeRateLimit(resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get(headerRateLimitRemaining))
+	if err != nil {
+		return
+	}
+
+	reset, err := strconv.ParseInt(resp.Header.Get(headerRateLimitReset), 10, 64)
+	if err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.remaining = remaining
+	t.reset = time.Unix(reset, 0)
+}
+
+func (t *Retry) waitForRateLimit(ctx context.Context) error {
+	t.mu.Lock()
+	remaining, reset := t.remaining, t.reset
+
+	if remaining >= 0 {
+		t.remaining--
+	}
+	t.mu.Unlock()
+
+	if remaining < 0 || remaining > rateLimitReserve {
+		return nil
+	}
+
+	wait := time.Until(reset)
+	if wait <= 0 {
+		return nil
+	}
+
+	return sleep(ctx, wait)
+}
+
+func retryAfter(resp *http.Response) time.Duration {
+	if seconds, err := strconv.Atoi(resp.Header.Get(headerRetryAfter)); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if reset, err := strconv.ParseInt(resp.Header.Get(headerRateLimitReset), 10, 64); err == nil {
+			return time.Until(time.Unix(reset, 0))
+		}
+	}
+
+	return 0
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transport_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edsonmichaque/dnsimple-cli/internal/transport"
+)
+
+const testBackoff = 10 * time.Millisecond
+
+// flakyServer answers the requests with the statuses in order, then with 200,
+// and records the bodies it receives.
+type flakyServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	header   http.Header
+	bodies   []string
+}
+
+func newFlakyServer(t *testing.T, statuses ...int) *flakyServer {
+	t.Helper()
+
+	s := &flakyServer{statuses: statuses, header: make(http.Header)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.mu.Lock()
+		s.bodies = append(s.bodies, string(body))
+
+		status := http.StatusOK
+		if len(s.statuses) != 0 {
+			status, s.statuses = s.statuses[0], s.statuses[1:]
+		}
+
+		for name, values := range s.header {
+			w.Header()[name] = values
+		}
+		s.mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *flakyServer) requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.bodies)
+}
+
+func newRetry(maxRetries int) *transport.Retry {
+	retry := transport.NewRetry(http.DefaultTransport, maxRetries)
+	retry.MinBackoff = testBackoff
+	retry.MaxBackoff = 10 * testBackoff
+
+	return retry
+}
+
+func roundTrip(t *testing.T, rt http.RoundTripper, method, url, body string) *http.Response {
+	t.Helper()
+
+	var r io.Reader
+	if body != "" {
+		r = bytes.NewBufferString(body)
+	}
+
+	req, err := http.NewRequest(method, url, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	return resp
+}
+
+func TestRetryServerErrors(t *testing.T) {
+	server := newFlakyServer(t, http.StatusBadGateway, http.StatusServiceUnavailable)
+
+	start := time.Now()
+	resp := roundTrip(t, newRetry(3), http.MethodGet, server.URL, "")
+
+	if resp.StatusCode != http.StatusOK || server.requests() != 3 {
+		t.Errorf("got %d after %d request(s), want 200 after 3", resp.StatusCode, server.requests())
+	}
+
+	// The first wait is at least half of the minimum backoff and the second
+	// one at least the minimum backoff.
+	if elapsed := time.Since(start); elapsed < testBackoff*3/2 {
+		t.Errorf("retried within %v, want a backoff", elapsed)
+	}
+}
+
+func TestRetryTooManyRequests(t *testing.T) {
+	server := newFlakyServer(t, http.StatusTooManyRequests)
+	server.header.Set("Retry-After", "1")
+
+	start := time.Now()
+	resp := roundTrip(t, newRetry(3), http.MethodPost, server.URL, `{"name":"example.com"}`)
+
+	if resp.StatusCode != http.StatusOK || server.requests() != 2 {
+		t.Errorf("got %d after %d request(s), want 200 after 2", resp.StatusCode, server.requests())
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want Retry-After to be honoured", elapsed)
+	}
+}
+
+func TestRetryDoesNotRetryNonIdempotentRequests(t *testing.T) {
+	for _, method := range []string{http.MethodPost, http.MethodPatch} {
+		t.Run(method, func(t *testing.T) {
+			server := newFlakyServer(t, http.StatusInternalServerError)
+
+			resp := roundTrip(t, newRetry(3), method, server.URL, `{}`)
+
+			if resp.StatusCode != http.StatusInternalServerError || server.requests() != 1 {
+				t.Errorf("got %d after %d request(s), want 500 after 1", resp.StatusCode, server.requests())
+			}
+		})
+	}
+}
+
+func TestRetryIsBounded(t *testing.T) {
+	server := newFlakyServer(t, 500, 500, 500, 500, 500, 500)
+
+	resp := roundTrip(t, newRetry(2), http.MethodGet, server.URL, "")
+
+	if resp.StatusCode != http.StatusInternalServerError || server.requests() != 3 {
+		t.Errorf("got %d after %d request(s), want 500 after 3", resp.StatusCode, server.requests())
+	}
+}
+
+func TestRetryResendsBodyWithoutModifyingRequest(t *testing.T) {
+	server := newFlakyServer(t, http.StatusServiceUnavailable)
+
+	req, err := http.NewRequest(http.MethodPut, server.URL, bytes.NewBufferString(`{"content":"192.0.2.1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := req.Body
+
+	resp, err := newRetry(3).RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if req.Body != body {
+		t.Error("the body of the request was replaced")
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	if len(server.bodies) != 2 || server.bodies[0] != server.bodies[1] || server.bodies[1] == "" {
+		t.Errorf("received bodies %q, want the same body twice", server.bodies)
+	}
+}
+
+func TestRetryWaitsForRateLimitReset(t *testing.T) {
+	server := newFlakyServer(t)
+	server.header.Set("X-RateLimit-Remaining", "1")
+	server.header.Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(2*time.Second).Unix(), 10))
+
+	retry := newRetry(3)
+	roundTrip(t, retry, http.MethodGet, server.URL, "")
+
+	start := time.Now()
+	roundTrip(t, retry, http.MethodGet, server.URL, "")
+
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+		t.Errorf("sent the request after %v, want it held until the rate limit resets", elapsed)
+	}
+}
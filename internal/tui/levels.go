@@ -0,0 +1,386 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/format"
+	"github.com/rivo/tview"
+)
+
+// recordTypes lists the record types offered when adding a record.
+var recordTypes = []string{
+	"A", "AAAA", "ALIAS", "CAA", "CNAME", "HINFO", "MX", "NAPTR",
+	"NS", "PTR", "SPF", "SRV", "SSHFP", "TXT", "URL",
+}
+
+func (a *App) accountsLevel() *level {
+	entries := make([]entry, 0, len(a.accounts))
+
+	for _, account := range a.accounts {
+		account := account
+
+		entries = append(entries, entry{
+			label:  fmt.Sprintf("%-10d %s", account.ID, account.Email),
+			detail: render(format.AccountItem(account)),
+			open: func() *level {
+				return a.domainsLevel(account)
+			},
+		})
+	}
+
+	return &level{title: "Accounts", entries: entries}
+}
+
+func (a *App) domainsLevel(account dnsimple.Account) *level {
+	accountID := strconv.FormatInt(account.ID, 10)
+
+	return &level{
+		title: account.Email,
+		load: func(ctx context.Context) ([]entry, error) {
+			domains, err := listAll(func(page int) ([]dnsimple.Domain, *dnsimple.Pagination, error) {
+				resp, err := a.client.Domains.ListDomains(ctx, accountID, &dnsimple.DomainListOptions{
+					ListOptions: dnsimple.ListOptions{Page: dnsimple.Int(page)},
+				})
+				if err != nil {
+					return nil, nil, err
+				}
+
+				return resp.Data, resp.Pagination, nil
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			entries := make([]entry, 0, len(domains))
+
+			for _, domain := range domains {
+				domain := domain
+
+				entries = append(entries, entry{
+					label:  fmt.Sprintf("%-40s %s", domain.Name, domain.State),
+					detail: render(format.DomainItem(dnsimple.DomainResponse{Data: &domain})),
+					open: func() *level {
+						return a.domainLevel(accountID, domain)
+					},
+					remove: &removal{
+						prompt: fmt.Sprintf("Delete domain %s and its zone? The zone is backed up first.", domain.Name),
+						do: func(ctx context.Context) error {
+							return a.deleteDomain(ctx, accountID, domain)
+						},
+					},
+				})
+			}
+
+			return entries, nil
+		},
+	}
+}
+
+func (a *App) domainLevel(accountID string, domain dnsimple.Domain) *level {
+	detail := render(format.DomainItem(dnsimple.DomainResponse{Data: &domain}))
+
+	return &level{
+		title: domain.Name,
+		entries: []entry{
+			{
+				label:  "Zone records",
+				detail: detail,
+				open: func() *level {
+					return a.recordsLevel(accountID, domain.Name)
+				},
+			},
+			{
+				label:  "Delegation signer records",
+				detail: detail,
+				open: func() *level {
+					return a.delegationSignerRecordsLevel(accountID, domain.Name)
+				},
+			},
+			{
+				label:  "Collaborators",
+				detail: detail,
+				open: func() *level {
+					return a.collaboratorsLevel(accountID, domain.Name)
+				},
+			},
+		},
+	}
+}
+
+func (a *App) recordsLevel(accountID, zone string) *level {
+	l := &level{title: "Records"}
+
+	l.add = func() {
+		a.recordForm("Add record to "+zone, nil, func(ctx context.Context, attrs dnsimple.ZoneRecordAttributes) error {
+			_, err := a.client.Zones.CreateRecord(ctx, accountID, zone, attrs)
+
+			return err
+		})
+	}
+
+	l.load = func(ctx context.Context) ([]entry, error) {
+		records, err := listAll(func(page int) ([]dnsimple.ZoneRecord, *dnsimple.Pagination, error) {
+			resp, err := a.client.Zones.ListRecords(ctx, accountID, zone, &dnsimple.ZoneRecordListOptions{
+				ListOptions: dnsimple.ListOptions{Page: dnsimple.Int(page)},
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+
+			return resp.Data, resp.Pagination, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		entries := make([]entry, 0, len(records))
+
+		for _, record := range records {
+			record := record
+
+			e := entry{
+				label:  fmt.Sprintf("%-24s %-6s %s", recordName(record.Name), record.Type, record.Content),
+				detail: render(format.ZoneRecordItem(dnsimple.ZoneRecordResponse{Data: &record})),
+			}
+
+			// System records are managed by DNSimple.
+			if !record.SystemRecord {
+				e.edit = func() {
+					title := fmt.Sprintf("Edit %s record %s", record.Type, recordName(record.Name))
+
+					a.recordForm(title, &record, func(ctx context.Context, attrs dnsimple.ZoneRecordAttributes) error {
+						_, err := a.client.Zones.UpdateRecord(ctx, accountID, zone, record.ID, attrs)
+
+						return err
+					})
+				}
+
+				e.remove = &removal{
+					prompt: fmt.Sprintf("Delete %s record %s %s from %s?", record.Type, recordName(record.Name), record.Content, zone),
+					do: func(ctx context.Context) error {
+						_, err := a.client.Zones.DeleteRecord(ctx, accountID, zone, record.ID)
+
+						return err
+					},
+				}
+			}
+
+			entries = append(entries, e)
+		}
+
+		return entries, nil
+	}
+
+	return l
+}
+
+// recordForm lets the user fill the attributes of a new record, or change
+// those of record, and calls save with them once confirmed.
+func (a *App) recordForm(title string, record *dnsimple.ZoneRecord, save func(ctx context.Context, attrs dnsimple.ZoneRecordAttributes) error) {
+	var (
+		recordType string
+		name       string
+		content    string
+		ttl        = "3600"
+		priority   = "0"
+	)
+
+	if record != nil {
+		recordType = record.Type
+		name = record.Name
+		content = record.Content
+		ttl = strconv.Itoa(record.TTL)
+		priority = strconv.Itoa(record.Priority)
+	}
+
+	form := tview.NewForm()
+	form.SetTitle(" " + tview.Escape(title) + " ")
+
+	// The type of a record cannot change.
+	if record == nil {
+		recordType = recordTypes[0]
+
+		form.AddDropDown("Type", recordTypes, 0, func(option string, _ int) {
+			recordType = option
+		})
+	}
+
+	form.
+		AddInputField("Name", name, 40, nil, func(text string) { name = text }).
+		AddInputField("Content", content, 40, nil, func(text string) { content = text }).
+		AddInputField("TTL", ttl, 10, tview.InputFieldInteger, func(text string) { ttl = text }).
+		AddInputField("Priority", priority, 10, tview.InputFieldInteger, func(text string) { priority = text }).
+		AddButton("Save", func() {
+			attrs, err := recordFormAttributes(recordType, name, content, ttl, priority)
+			if err != nil {
+				a.status.SetText("[red]Error: " + tview.Escape(err.Error()))
+
+				return
+			}
+
+			prompt := fmt.Sprintf("Save %s record %s %s?", attrs.Type, recordName(name), content)
+			if record != nil {
+				prompt = fmt.Sprintf("Change %s record %s to %s %s?", record.Type, recordName(record.Name), recordName(name), content)
+			}
+
+			a.confirm(prompt, "Save", func() {
+				a.closeForm()
+				a.background("Saving", func(ctx context.Context) error {
+					return save(ctx, attrs)
+				}, a.reload)
+			})
+		}).
+		AddButton("Cancel", a.closeForm)
+
+	a.showForm(form)
+}
+
+func recordFormAttributes(recordType, name, content, ttl, priority string) (dnsimple.ZoneRecordAttributes, error) {
+	attrs := dnsimple.ZoneRecordAttributes{
+		Type:    recordType,
+		Name:    &name,
+		Content: content,
+	}
+
+	var err error
+
+	if attrs.TTL, err = strconv.Atoi(ttl); err != nil {
+		return attrs, fmt.Errorf("invalid TTL %q", ttl)
+	}
+
+	if attrs.Priority, err = strconv.Atoi(priority); err != nil {
+		return attrs, fmt.Errorf("invalid priority %q", priority)
+	}
+
+	return attrs, nil
+}
+
+func (a *App) delegationSignerRecordsLevel(accountID, domain string) *level {
+	return &level{
+		title: "Delegation signer records",
+		load: func(ctx context.Context) ([]entry, error) {
+			records, err := listAll(func(page int) ([]dnsimple.DelegationSignerRecord, *dnsimple.Pagination, error) {
+				resp, err := a.client.DelegationSignerRecords.ListDelegationSignerRecords(ctx, accountID, domain, &dnsimple.ListOptions{
+					Page: dnsimple.Int(page),
+				})
+				if err != nil {
+					return nil, nil, err
+				}
+
+				return resp.Data, resp.Pagination, nil
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			entries := make([]entry, 0, len(records))
+
+			for _, record := range records {
+				record := record
+
+				entries = append(entries, entry{
+					label:  fmt.Sprintf("%-10d keytag %s, algorithm %s", record.ID, record.Keytag, record.Algorithm),
+					detail: render(format.DSRItem(dnsimple.DelegationSignerRecordResponse{Data: &record})),
+					remove: &removal{
+						prompt: fmt.Sprintf("Delete delegation signer record %d of %s?", record.ID, domain),
+						do: func(ctx context.Context) error {
+							_, err := a.client.DelegationSignerRecords.DeleteDelegationSignerRecord(ctx, accountID, domain, record.ID)
+
+							return err
+						},
+					},
+				})
+			}
+
+			return entries, nil
+		},
+	}
+}
+
+func (a *App) collaboratorsLevel(accountID, domain string) *level {
+	return &level{
+		title: "Collaborators",
+		load: func(ctx context.Context) ([]entry, error) {
+			collaborators, err := listAll(func(page int) ([]dnsimple.Collaborator, *dnsimple.Pagination, error) {
+				resp, err := a.client.Collaborators.ListCollaborators(ctx, accountID, domain, &dnsimple.ListOptions{
+					Page: dnsimple.Int(page),
+				})
+				if err != nil {
+					return nil, nil, err
+				}
+
+				return resp.Data, resp.Pagination, nil
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			entries := make([]entry, 0, len(collaborators))
+
+			for _, collaborator := range collaborators {
+				collaborator := collaborator
+
+				entries = append(entries, entry{
+					label:  collaborator.UserEmail,
+					detail: render(format.CollaboratorItem(dnsimple.CollaboratorResponse{Data: &collaborator})),
+					remove: &removal{
+						prompt: fmt.Sprintf("Remove collaborator %s from %s?", collaborator.UserEmail, domain),
+						do: func(ctx context.Context) error {
+							_, err := a.client.Collaborators.RemoveCollaborator(ctx, accountID, domain, collaborator.ID)
+
+							return err
+						},
+					},
+				})
+			}
+
+			return entries, nil
+		},
+	}
+}
+
+// listAll calls fetch with page numbers starting at 1 until the last page
+// and returns the items of every page.
+func listAll[T any](fetch func(page int) ([]T, *dnsimple.Pagination, error)) ([]T, error) {
+	var items []T
+
+	for page := 1; ; page++ {
+		data, pagination, err := fetch(page)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, data...)
+
+		if pagination == nil || page >= pagination.TotalPages {
+			return items, nil
+		}
+	}
+}
+
+func recordName(name string) string {
+	if name == "" {
+		return "@"
+	}
+
+	return name
+}
@@ -0,0 +1,425 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tui implements a full-screen terminal browser of accounts, their
+// domains and the records, delegation signer records and collaborators of
+// each domain.
+package tui
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/api"
+	"github.com/edsonmichaque/dnsimple-cli/internal/format"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	pageMain    = "main"
+	pageForm    = "form"
+	pageConfirm = "confirm"
+)
+
+// DeleteDomainFunc deletes domain from the account with the given ID. The UI
+// leaves to it the checks and backups that must come first.
+type DeleteDomainFunc func(ctx context.Context, accountID string, domain dnsimple.Domain) error
+
+// App is the terminal UI. Each screen lists the entries of a level, such as
+// the domains of an account, and Enter opens the level below the selected
+// entry.
+type App struct {
+	client       *api.Client
+	accounts     []dnsimple.Account
+	deleteDomain DeleteDomainFunc
+
+	ctx    context.Context
+	app    *tview.Application
+	pages  *tview.Pages
+	list   *tview.List
+	detail *tview.TextView
+	filter *tview.InputField
+	status *tview.TextView
+
+	stack []*level
+}
+
+// level is a screen of the browser.
+type level struct {
+	title string
+	load  func(ctx context.Context) ([]entry, error)
+	// add, if set, lets the user create an entry of the level.
+	add func()
+
+	entries  []entry
+	visible  []int
+	filter   string
+	selected int
+}
+
+type entry struct {
+	label  string
+	detail string
+	// open, if set, returns the level below the entry.
+	open func() *level
+	// edit, if set, lets the user change the entry.
+	edit func()
+	// remove, if set, deletes the entry after the user confirmed prompt.
+	remove *removal
+}
+
+type removal struct {
+	prompt string
+	do     func(ctx context.Context) error
+}
+
+// New returns the UI of client, starting with the list of accounts. Domains
+// are deleted with deleteDomain.
+func New(client *api.Client, accounts []dnsimple.Account, deleteDomain DeleteDomainFunc) *App {
+	a := &App{
+		client:       client,
+		accounts:     accounts,
+		deleteDomain: deleteDomain,
+		app:          tview.NewApplication(),
+		pages:        tview.NewPages(),
+		list:         tview.NewList().ShowSecondaryText(false),
+		detail:       tview.NewTextView(),
+		filter:       tview.NewInputField().SetLabel("/ "),
+		status:       tview.NewTextView().SetDynamicColors(true),
+	}
+
+	a.list.SetBorder(true)
+	a.list.SetHighlightFullLine(true)
+	a.list.SetChangedFunc(func(i int, _, _ string, _ rune) {
+		a.showDetail(i)
+	})
+	a.list.SetSelectedFunc(func(i int, _, _ string, _ rune) {
+		a.open(i)
+	})
+	a.list.SetInputCapture(a.listKeys)
+
+	a.detail.SetBorder(true).SetTitle(" Details ")
+
+	a.filter.SetChangedFunc(func(text string) {
+		a.top().filter = text
+		a.render()
+	})
+	a.filter.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			a.filter.SetText("")
+		}
+
+		a.app.SetFocus(a.list)
+	})
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(tview.NewFlex().
+			AddItem(a.list, 0, 3, true).
+			AddItem(a.detail, 0, 2, false), 0, 1, true).
+		AddItem(a.filter, 1, 0, false).
+		AddItem(a.status, 1, 0, false)
+
+	a.pages.AddPage(pageMain, layout, true, true)
+	a.app.SetRoot(a.pages, true)
+
+	return a
+}
+
+// Run shows the UI until the user quits or ctx is done.
+func (a *App) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	a.ctx = ctx
+
+	go func() {
+		<-ctx.Done()
+		a.app.Stop()
+	}()
+
+	a.push(a.accountsLevel())
+
+	// An account token only gives access to its own account.
+	if len(a.accounts) == 1 {
+		a.push(a.domainsLevel(a.accounts[0]))
+	}
+
+	return a.app.Run()
+}
+
+func (a *App) top() *level {
+	return a.stack[len(a.stack)-1]
+}
+
+func (a *App) push(l *level) {
+	a.stack = append(a.stack, l)
+	a.filter.SetText("")
+	a.reload()
+}
+
+func (a *App) pop() {
+	if len(a.stack) == 1 {
+		return
+	}
+
+	a.stack = a.stack[:len(a.stack)-1]
+	a.filter.SetText(a.top().filter)
+	a.render()
+}
+
+// reload loads the entries of the current level again.
+func (a *App) reload() {
+	l := a.top()
+	if l.load == nil {
+		a.render()
+
+		return
+	}
+
+	var entries []entry
+
+	a.background("Loading "+l.title, func(ctx context.Context) error {
+		var err error
+
+		entries, err = l.load(ctx)
+
+		return err
+	}, func() {
+		l.entries = entries
+		if a.top() == l {
+			a.render()
+		}
+	})
+}
+
+// render shows the entries of the current level that match its filter.
+func (a *App) render() {
+	l := a.top()
+
+	l.visible = l.visible[:0]
+
+	for i, e := range l.entries {
+		if l.filter == "" || strings.Contains(strings.ToLower(e.label), strings.ToLower(l.filter)) {
+			l.visible = append(l.visible, i)
+		}
+	}
+
+	selected := l.selected
+
+	a.list.Clear()
+
+	for _, i := range l.visible {
+		a.list.AddItem(tview.Escape(l.entries[i].label), "", 0, nil)
+	}
+
+	titles := make([]string, 0, len(a.stack))
+	for _, l := range a.stack {
+		titles = append(titles, l.title)
+	}
+
+	a.list.SetTitle(" " + tview.Escape(strings.Join(titles, " › ")) + " ")
+
+	if selected >= len(l.visible) {
+		selected = len(l.visible) - 1
+	}
+
+	if selected < 0 {
+		selected = 0
+	}
+
+	a.list.SetCurrentItem(selected)
+	a.showDetail(selected)
+}
+
+// current returns the selected entry of the current level, if any.
+func (a *App) current(i int) *entry {
+	l := a.top()
+	if i < 0 || i >= len(l.visible) {
+		return nil
+	}
+
+	return &l.entries[l.visible[i]]
+}
+
+func (a *App) showDetail(i int) {
+	a.top().selected = i
+
+	if e := a.current(i); e != nil {
+		a.detail.SetText(e.detail)
+	} else {
+		a.detail.SetText("")
+	}
+
+	a.showHints()
+}
+
+func (a *App) open(i int) {
+	if e := a.current(i); e != nil && e.open != nil {
+		a.push(e.open())
+	}
+}
+
+func (a *App) listKeys(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyEscape, tcell.KeyBackspace, tcell.KeyBackspace2, tcell.KeyLeft:
+		if a.top().filter != "" {
+			a.filter.SetText("")
+		} else {
+			a.pop()
+		}
+
+		return nil
+	case tcell.KeyRight:
+		a.open(a.list.GetCurrentItem())
+
+		return nil
+	case tcell.KeyRune:
+	default:
+		return event
+	}
+
+	e := a.current(a.list.GetCurrentItem())
+
+	switch event.Rune() {
+	case '/':
+		a.app.SetFocus(a.filter)
+	case 'q':
+		a.app.Stop()
+	case 'r':
+		a.reload()
+	case 'a':
+		if add := a.top().add; add != nil {
+			add()
+		}
+	case 'e':
+		if e != nil && e.edit != nil {
+			e.edit()
+		}
+	case 'd':
+		if e != nil && e.remove != nil {
+			r := e.remove
+			a.confirm(r.prompt, "Delete", func() {
+				a.background("Deleting", r.do, a.reload)
+			})
+		}
+	default:
+		return event
+	}
+
+	return nil
+}
+
+func (a *App) showHints() {
+	hints := []string{"enter open", "esc back", "/ filter", "r reload"}
+
+	if a.top().add != nil {
+		hints = append(hints, "a add")
+	}
+
+	if e := a.current(a.list.GetCurrentItem()); e != nil {
+		if e.edit != nil {
+			hints = append(hints, "e edit")
+		}
+
+		if e.remove != nil {
+			hints = append(hints, "d delete")
+		}
+	}
+
+	hints = append(hints, "q quit")
+
+	a.status.SetText("[gray]" + strings.Join(hints, " · "))
+}
+
+// background runs fn outside of the event loop while showing msg, then
+// calls done in the event loop if fn succeeded or shows its error.
+func (a *App) background(msg string, fn func(ctx context.Context) error, done func()) {
+	a.status.SetText("[yellow]" + tview.Escape(msg) + "…")
+
+	go func() {
+		err := fn(a.ctx)
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.status.SetText("[red]Error: " + tview.Escape(err.Error()))
+
+				return
+			}
+
+			a.showHints()
+
+			if done != nil {
+				done()
+			}
+		})
+	}()
+}
+
+// confirm asks the user to confirm prompt and calls fn if they do.
+func (a *App) confirm(prompt, action string, fn func()) {
+	modal := tview.NewModal().
+		SetText(prompt).
+		AddButtons([]string{"Cancel", action}).
+		SetDoneFunc(func(_ int, label string) {
+			a.pages.RemovePage(pageConfirm)
+
+			if label == action {
+				fn()
+			}
+		})
+
+	a.pages.AddPage(pageConfirm, modal, true, true)
+}
+
+// showForm shows form on top of the browser until closeForm is called.
+func (a *App) showForm(form *tview.Form) {
+	form.SetBorder(true)
+	form.SetCancelFunc(a.closeForm)
+
+	a.pages.AddPage(pageForm, centered(form, 60, 15), true, true)
+}
+
+func (a *App) closeForm() {
+	a.pages.RemovePage(pageForm)
+}
+
+func centered(p tview.Primitive, width, height int) tview.Primitive {
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, height, 0, true).
+			AddItem(nil, 0, 1, false), width, 0, true).
+		AddItem(nil, 0, 1, false)
+}
+
+// render formats v as text with the formatters of the commands.
+func render(v interface{}) string {
+	r, err := format.Format(v, &format.Options{Format: format.OutputFormatText})
+	if err != nil {
+		return err.Error()
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(data)
+}
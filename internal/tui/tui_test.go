@@ -0,0 +1,222 @@
+// Copyright 2023 Edson Michaque
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tui
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dnsimple/dnsimple-go/dnsimple"
+	"github.com/edsonmichaque/dnsimple-cli/internal/api"
+	"github.com/edsonmichaque/dnsimple-cli/internal/fakeapi"
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestDeleteRecord(t *testing.T) {
+	account := dnsimple.Account{ID: 1, Email: "dev@example.com"}
+
+	server := fakeapi.New(account)
+	if _, err := server.AddDomain(account.ID, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	client := newClient(t, server)
+
+	name := "www"
+	if _, err := client.Zones.CreateRecord(context.Background(), "1", "example.com", dnsimple.ZoneRecordAttributes{
+		Type:    "A",
+		Name:    &name,
+		Content: "203.0.113.10",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	app, screen := start(t, New(client, []dnsimple.Account{account}, nil))
+
+	waitForText(t, app, screen, "hosted")
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	waitForText(t, app, screen, "Zone records")
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	waitForText(t, app, screen, "203.0.")
+
+	for _, r := range "/www" {
+		screen.InjectKey(tcell.KeyRune, r, tcell.ModNone)
+	}
+
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	screen.InjectKey(tcell.KeyRune, 'd', tcell.ModNone)
+	waitForText(t, app, screen, "Delete A record www")
+
+	// The focus starts on Cancel.
+	screen.InjectKey(tcell.KeyTab, 0, tcell.ModNone)
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for {
+		resp, err := client.Zones.ListRecords(context.Background(), "1", "example.com", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		deleted := true
+
+		for _, record := range resp.Data {
+			if record.Name == name {
+				deleted = false
+			}
+		}
+
+		if deleted {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("the record was not deleted")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDeleteDomain(t *testing.T) {
+	account := dnsimple.Account{ID: 1, Email: "dev@example.com"}
+
+	server := fakeapi.New(account)
+	if _, err := server.AddDomain(account.ID, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted := make(chan string, 1)
+
+	deleteDomain := func(ctx context.Context, accountID string, domain dnsimple.Domain) error {
+		deleted <- accountID + "/" + domain.Name
+
+		return errors.New("refusing to delete registered domain")
+	}
+
+	app, screen := start(t, New(newClient(t, server), []dnsimple.Account{account}, deleteDomain))
+
+	waitForText(t, app, screen, "dev@example.com")
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	waitForText(t, app, screen, "hosted")
+	screen.InjectKey(tcell.KeyRune, 'd', tcell.ModNone)
+	waitForText(t, app, screen, "Delete domain example.com")
+
+	screen.InjectKey(tcell.KeyTab, 0, tcell.ModNone)
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+
+	select {
+	case got := <-deleted:
+		if want := "1/example.com"; got != want {
+			t.Errorf("deleted %s, want %s", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the domain was not deleted with the delete function")
+	}
+
+	waitForText(t, app, screen, "Error: refusing to delete registered domain")
+}
+
+// newClient returns a client of server.
+func newClient(t *testing.T, server *fakeapi.Server) *api.Client {
+	t.Helper()
+
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+
+	dc := dnsimple.NewClient(dnsimple.StaticTokenHTTPClient(context.Background(), fakeapi.Token))
+	dc.BaseURL = ts.URL
+
+	return api.New(dc)
+}
+
+// start runs app on a simulated screen until the test ends.
+func start(t *testing.T, app *App) (*App, tcell.SimulationScreen) {
+	t.Helper()
+
+	screen := tcell.NewSimulationScreen("UTF-8")
+	app.app.SetScreen(screen)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() {
+		done <- app.Run(ctx)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+
+		if err := <-done; err != nil {
+			t.Error(err)
+		}
+	})
+
+	return app, screen
+}
+
+func waitForText(t *testing.T, app *App, screen tcell.SimulationScreen, text string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for {
+		got := screenText(app, screen)
+		if strings.Contains(got, text) {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("%q not shown on screen:\n%s", text, got)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// screenText returns the text shown on screen. It is read in the event loop
+// of app, which draws the screen.
+func screenText(app *App, screen tcell.SimulationScreen) string {
+	text := make(chan string, 1)
+
+	app.app.QueueUpdate(func() {
+		text <- screenContents(screen)
+	})
+
+	return <-text
+}
+
+func screenContents(screen tcell.SimulationScreen) string {
+	cells, width, _ := screen.GetContents()
+
+	var b strings.Builder
+
+	for i, cell := range cells {
+		if i > 0 && i%width == 0 {
+			b.WriteByte('\n')
+		}
+
+		b.WriteString(string(cell.Runes))
+	}
+
+	return b.String()
+}